@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/chaosblade-io/chaosblade-exec-os/exec/model"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// Args builds the argv of a command. Every flag is one element of the argv, so the flag values are passed
+// end to end even if they contain spaces, quotes or the base64 content
+type Args struct {
+	argv []string
+}
+
+// NewArgs returns the builder whose argv starts with the arguments
+func NewArgs(args ...string) *Args {
+	return &Args{argv: append([]string{}, args...)}
+}
+
+// Add appends the arguments as they are
+func (a *Args) Add(args ...string) *Args {
+	a.argv = append(a.argv, args...)
+	return a
+}
+
+// Flag appends the flag in the form of --name=value
+func (a *Args) Flag(name, value string) *Args {
+	a.argv = append(a.argv, fmt.Sprintf("--%s=%s", name, value))
+	return a
+}
+
+// Flags appends the non-empty flags in the order of the names, the excluded flags are skipped
+func (a *Args) Flags(flags map[string]string, excludes map[string]spec.Empty) *Args {
+	names := make([]string, 0, len(flags))
+	for name, value := range flags {
+		if value == "" {
+			continue
+		}
+		if _, ok := excludes[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a.Flag(name, flags[name])
+	}
+	return a
+}
+
+// Argv returns the built argv
+func (a *Args) Argv() []string {
+	return append([]string{}, a.argv...)
+}
+
+// chaosOsArgs returns the argv of chaos_os which executes the experiment in the namespaces of the pid by nsexec.
+// The container flags and the timeout handled by the revert watcher are not passed to chaos_os
+func chaosOsArgs(ctx context.Context, uid string, expModel *spec.ExpModel, pid int32, namespaces []string) []string {
+	excludes := GetAllDockerFlagNames()
+	excludes[TimeoutFlag] = spec.Empty{}
+
+	command := spec.Create
+	if _, ok := spec.IsDestroy(ctx); ok {
+		command = spec.Destroy
+	}
+	args := NewArgs(command, expModel.Target, expModel.ActionName).
+		Flags(expModel.ActionFlags, excludes).
+		Flag("uid", uid).
+		Flag(model.ChannelFlag.Name, spec.NSExecBin).
+		Flag(model.NsTargetFlag.Name, strconv.Itoa(int(pid)))
+	for _, nsFlag := range chaosOsNamespaceArgs(namespaces) {
+		args.Flag(nsFlag, spec.True)
+	}
+	return args.Argv()
+}
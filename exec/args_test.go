@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-exec-os/exec/model"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args *Args
+		want []string
+	}{
+		{"empty", NewArgs(), []string{}},
+		{"add", NewArgs("create").Add("cpu", "fullload"), []string{"create", "cpu", "fullload"}},
+		{"flag keeps the value as one argument", NewArgs().Flag("script", `echo "a b" 'c'`),
+			[]string{`--script=echo "a b" 'c'`}},
+		{"flags are sorted, the empty and excluded ones are skipped", NewArgs().Flags(map[string]string{
+			"timeout": "10", "cpu-percent": "50", "cpu-count": "", "container-id": "abc",
+		}, map[string]spec.Empty{"container-id": {}, "timeout": {}}), []string{"--cpu-percent=50"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.Argv(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Argv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgsArgvIsCopied(t *testing.T) {
+	args := NewArgs("create")
+	argv := args.Argv()
+	argv[0] = "destroy"
+	if got := args.Argv()[0]; got != "create" {
+		t.Errorf("Argv()[0] = %s after the returned argv is modified, want create", got)
+	}
+}
+
+func TestChaosOsArgs(t *testing.T) {
+	expModel := &spec.ExpModel{
+		Target:     "network",
+		ActionName: "delay",
+		ActionFlags: map[string]string{
+			"time":                 "3000",
+			"interface":            "eth0",
+			"exclude-port":         "22, 8080",
+			ContainerIdFlag.Name:   "abc",
+			ContainerNameFlag.Name: "nginx",
+			TimeoutFlag:            "60",
+		},
+	}
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		namespaces []string
+		want       []string
+	}{
+		{
+			name:       "create",
+			ctx:        context.Background(),
+			namespaces: []string{NamespacePid, NamespaceNet},
+			want: []string{spec.Create, "network", "delay", "--exclude-port=22, 8080", "--interface=eth0", "--time=3000",
+				"--uid=uid", "--" + model.ChannelFlag.Name + "=" + spec.NSExecBin, "--" + model.NsTargetFlag.Name + "=100",
+				"--" + model.NsPidFlag.Name + "=true", "--" + model.NsNetFlag.Name + "=true"},
+		},
+		{
+			name:       "destroy",
+			ctx:        spec.SetDestroyFlag(context.Background(), "uid"),
+			namespaces: []string{NamespaceMnt, NamespaceUts},
+			want: []string{spec.Destroy, "network", "delay", "--exclude-port=22, 8080", "--interface=eth0", "--time=3000",
+				"--uid=uid", "--" + model.ChannelFlag.Name + "=" + spec.NSExecBin, "--" + model.NsTargetFlag.Name + "=100",
+				"--" + model.NsMntFlag.Name + "=true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chaosOsArgs(tt.ctx, "uid", expModel, 100, tt.namespaces); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chaosOsArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The codes of the cri experiments which are not defined by chaosblade-spec-go
+var (
+	ExperimentDenied    = spec.CodeType{Code: 68000, Msg: "the experiment is denied by the policy `%s`, %s"}
+	ContainerNotRunning = spec.CodeType{Code: 68001, Msg: "the container %s is %s, %s"}
+	ExperimentNotFound  = spec.CodeType{Code: 68002, Msg: "the cri experiment `%s` is not active on the node"}
+)
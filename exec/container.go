@@ -21,6 +21,8 @@ import (
 
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
 const (
@@ -110,26 +112,29 @@ func (e *removeActionExecutor) Exec(uid string, ctx context.Context, model *spec
 		return spec.ReturnSuccess(uid)
 	}
 	flags := model.ActionFlags
-	client, err := GetClientByRuntime(model)
+	client, detection, err := getClient(ctx, model)
 	if err != nil {
 		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("GetClient", err))
 		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "GetClient", err)
 	}
-	containerId := flags[ContainerIdFlag.Name]
-	containerName := flags[ContainerNameFlag.Name]
-	containerLabelSelector := parseContainerLabelSelector(flags[ContainerNameFlag.Name])
-	container, response := GetContainer(ctx, client, uid, containerId, containerName, containerLabelSelector)
+	containers, response := GetTargetContainers(ctx, client, uid, model)
 	if !response.Success {
-		return response
+		return withDetection(detection, response)
 	}
-	forceFlag := flags[ForceFlag]
-
-	err = client.RemoveContainer(ctx, container.ContainerId, judgeForce(forceFlag))
-	if err != nil {
-		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("ContainerRemove", err))
-		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "ContainerRemove", err)
-	}
-	return spec.ReturnSuccess(uid)
+	// the removed containers are not recorded, so the targets reserved by the policy are only released
+	defer releaseReservation(ctx, uid)
+	force := judgeForce(flags[ForceFlag])
+
+	// the removal can not be reverted, so the containers are not recorded in the state, and the containers
+	// which are removed are reported even if the others fail
+	_, responses := runInContainers(ctx, containers, func(ctx context.Context, info container.ContainerInfo) *spec.Response {
+		if err := client.RemoveContainer(ctx, info.ContainerId, force); err != nil {
+			log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("ContainerRemove", err))
+			return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "ContainerRemove", err)
+		}
+		return spec.ReturnSuccess(uid)
+	})
+	return withDetection(detection, aggregateResponses(uid, model, containers, responses, nil))
 }
 
 func judgeForce(forceflag string) bool {
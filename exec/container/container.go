@@ -17,18 +17,39 @@
 package container
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/containerd/typeurl/v2"
 	containertype "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 )
 
 const (
 	ContainerdRuntime = "containerd"
 	DockerRuntime     = "docker"
+	CrioRuntime       = "crio"
+	CriRuntime        = "cri"
+	PodmanRuntime     = "podman"
+)
+
+// The states of the container
+const (
+	StateCreated = "created"
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateExited  = "exited"
+	StateUnknown = "unknown"
 )
 
 const (
@@ -36,18 +57,135 @@ const (
 	DefaultImageRepo       = "registry.cn-hangzhou.aliyuncs.com/chaosblade/chaosblade-tool"
 )
 
+// SandboxRuntimes are the keywords of the runtimes which run the containers in a virtual machine or a user space
+// kernel, the processes of these containers can not be entered by nsexec on the local host
+var SandboxRuntimes = []string{"kata", "runsc", "gvisor"}
+
+// NSExecChecker is implemented by the clients which execute the commands by the exec api of the runtime when the
+// namespaces of the container process can not be entered by nsexec on the local host
+type NSExecChecker interface {
+	// CheckNSExec returns the reason why nsexec is not viable for the container, nil if it is viable
+	CheckNSExec(ctx context.Context, containerId string) error
+}
+
+type targetNamespaceKey struct{}
+
+// WithTargetNamespace returns the context which pins the containerd namespace of the target container, so the
+// container is not searched again in all namespaces by its id. The context is not changed if the namespace is empty
+func WithTargetNamespace(ctx context.Context, namespace string) context.Context {
+	if namespace == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, targetNamespaceKey{}, namespace)
+}
+
+// TargetNamespace returns the namespace pinned by WithTargetNamespace, or empty if it is not pinned
+func TargetNamespace(ctx context.Context) string {
+	namespace, _ := ctx.Value(targetNamespaceKey{}).(string)
+	return namespace
+}
+
+type nsexecChecksKey struct{}
+
+type nsexecChecks struct {
+	sync.Mutex
+	results map[string]error
+}
+
+// WithNSExecChecks returns the context which caches the results of CheckNSExec by the container id, so that the
+// container is inspected once in an experiment
+func WithNSExecChecks(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(nsexecChecksKey{}).(*nsexecChecks); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, nsexecChecksKey{}, &nsexecChecks{results: make(map[string]error)})
+}
+
+// CachedNSExecCheck returns the cached result of the check for the container, the check is run if there is no cache
+// in the context or the container is not checked yet. The results are cached by the pinned namespace and the id
+func CachedNSExecCheck(ctx context.Context, containerId string, check func() error) error {
+	checks, ok := ctx.Value(nsexecChecksKey{}).(*nsexecChecks)
+	if !ok {
+		return check()
+	}
+	key := TargetNamespace(ctx) + "/" + containerId
+	checks.Lock()
+	err, ok := checks.results[key]
+	checks.Unlock()
+	if ok {
+		return err
+	}
+	err = check()
+	checks.Lock()
+	checks.results[key] = err
+	checks.Unlock()
+	return err
+}
+
 type Container interface {
 	GetPidById(ctx context.Context, containerId string) (int32, error, int32)
 	GetContainerById(ctx context.Context, containerId string) (ContainerInfo, error, int32)
 	GetContainerByName(ctx context.Context, containerName string) (ContainerInfo, error, int32)
 	GetContainerByLabelSelector(containerLabelSelector map[string]string) (ContainerInfo, error, int32)
+	// GetContainers returns all the containers matched the filter
+	GetContainers(ctx context.Context, filter ContainerFilter) ([]ContainerInfo, error, int32)
 	RemoveContainer(ctx context.Context, containerId string, force bool) error
 	CopyToContainer(ctx context.Context, containerId, srcFile, dstPath, extractDirName string, override bool) error
 
-	ExecContainer(ctx context.Context, containerId, command string) (output string, err error)
+	// ExecContainer executes the command by /bin/sh -c in the container, the error is returned only if the command
+	// can not be executed, the non-zero exit code of the command is in the result
+	ExecContainer(ctx context.Context, containerId, command string) (ExecResult, error)
 	ExecuteAndRemove(ctx context.Context, config *containertype.Config, hostConfig *containertype.HostConfig,
 		networkConfig *network.NetworkingConfig, containerName string, removed bool, timeout time.Duration,
-		command string, containerInfo ContainerInfo) (containerId string, output string, err error, code int32)
+		command string, containerInfo ContainerInfo) (containerId string, result ExecResult, err error, code int32)
+}
+
+// ExecResult is the result of the command executed in the container
+type ExecResult struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Duration time.Duration
+}
+
+// Output returns the stdout, or the stderr if the stdout is empty
+func (r ExecResult) Output() string {
+	if stdout := strings.TrimSpace(r.Stdout); stdout != "" {
+		return stdout
+	}
+	return strings.TrimSpace(r.Stderr)
+}
+
+// Err returns an error if the command exits with the non-zero code
+func (r ExecResult) Err() error {
+	if r.ExitCode == 0 {
+		return nil
+	}
+	if output := r.Output(); output != "" {
+		return fmt.Errorf("command exit with code %d, %s", r.ExitCode, output)
+	}
+	return fmt.Errorf("command exit with code %d", r.ExitCode)
+}
+
+// ReadExecResult reads the multiplexed output of the exec api of docker and podman, and then inspects the exit code
+// of the exec. The exit code is required, otherwise the failure of the command can not be told
+func ReadExecResult(reader io.Reader, start time.Time, exitCode func() (int, error)) (ExecResult, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if _, err := stdcopy.StdCopy(stdout, stderr, reader); err != nil {
+		return ExecResult{}, fmt.Errorf("read the exec output failed, %v", err)
+	}
+	result := ExecResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	code, err := exitCode()
+	if err != nil {
+		return result, fmt.Errorf("inspect the exec failed, %v", err)
+	}
+	result.ExitCode = code
+	return result, nil
 }
 
 // ContainerInfo for server
@@ -56,6 +194,148 @@ type ContainerInfo struct {
 	ContainerName string
 	Labels        map[string]string
 	Spec          typeurl.Any
+	// Namespace is the containerd namespace where the container is found
+	Namespace string
+	// State is one of created, running, paused, exited and unknown
+	State   string
+	Pid     int32
+	Image   string
+	Created time.Time
+	Runtime string
+	// Sandbox is true if the runtime reports the container as the infra container of a pod without the labels,
+	// such as podman
+	Sandbox bool
+}
+
+// IsSandbox returns true if the container is the pause or the infra container of a pod
+func (info ContainerInfo) IsSandbox() bool {
+	return info.Sandbox || IsSandbox(info.Labels)
+}
+
+// ContainerFilter selects the containers, all the conditions which are not empty must be matched
+type ContainerFilter struct {
+	ContainerId   string
+	ContainerName string
+	// Labels are the equality requirements which are translated into the native filters of the runtimes
+	Labels map[string]string
+	// LabelSelector is evaluated by the client for the requirements which the runtimes do not support
+	LabelSelector LabelSelector
+	// Image is the image reference or the regular expression of the image
+	Image string
+	// NamePattern is the glob pattern or the regular expression of the container name, see MatchName
+	NamePattern string
+	// ExcludeLabelSelector excludes the containers whose labels match it
+	ExcludeLabelSelector LabelSelector
+	// ExcludeNames excludes the containers whose names match any of the names or the patterns
+	ExcludeNames []string
+	// IncludeSandbox includes the pause containers of the pods, they are skipped by default
+	IncludeSandbox bool
+	// Namespace is the containerd namespace of the container, it is not checked if it is empty
+	Namespace string
+}
+
+// IsEmpty returns true if the filter has no condition which selects the containers, the exclusions are not counted
+func (f ContainerFilter) IsEmpty() bool {
+	return f.ContainerId == "" && f.ContainerName == "" && len(f.Labels) == 0 && len(f.LabelSelector) == 0 &&
+		f.Image == "" && f.NamePattern == ""
+}
+
+// Match returns true if the container matches all the conditions of the filter. The container id can be a prefix
+func (f ContainerFilter) Match(info ContainerInfo) bool {
+	if f.ContainerId != "" && !strings.HasPrefix(info.ContainerId, f.ContainerId) {
+		return false
+	}
+	if !f.IncludeSandbox && info.IsSandbox() {
+		return false
+	}
+	if f.Namespace != "" && info.Namespace != f.Namespace {
+		return false
+	}
+	if f.ContainerName != "" && strings.TrimPrefix(info.ContainerName, "/") != strings.TrimPrefix(f.ContainerName, "/") {
+		return false
+	}
+	for k, v := range f.Labels {
+		if value, ok := info.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	if !MatchImage(f.Image, info.Image) {
+		return false
+	}
+	if !MatchName(f.NamePattern, info.ContainerName) {
+		return false
+	}
+	if len(f.ExcludeLabelSelector) > 0 && f.ExcludeLabelSelector.Matches(info.Labels) {
+		return false
+	}
+	if MatchAnyName(f.ExcludeNames, info.ContainerName) {
+		return false
+	}
+	return f.LabelSelector.Matches(info.Labels)
+}
+
+// SelectContainer returns the only container matched by the flag. It fails with the notFound code if no container
+// is matched, and fails with the ParameterInvalid code if multiple containers are matched
+func SelectContainer(containers []ContainerInfo, notFound spec.CodeType, flag, value string) (ContainerInfo, error, int32) {
+	switch len(containers) {
+	case 0:
+		return ContainerInfo{}, errors.New(notFound.Sprintf(flag)), notFound.Code
+	case 1:
+		return containers[0], nil, spec.OK.Code
+	}
+	ids := make([]string, 0, len(containers))
+	for _, ctr := range containers {
+		ids = append(ids, ctr.ContainerId)
+	}
+	reason := fmt.Sprintf("%d containers are matched: %s", len(containers), strings.Join(ids, ", "))
+	return ContainerInfo{}, errors.New(spec.ParameterInvalid.Sprintf(flag, value, reason)), spec.ParameterInvalid.Code
+}
+
+// FormatLabels returns the labels as the sorted key=value pairs separated by comma
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseState converts the state of docker or podman to the container state
+func ParseState(state string) string {
+	switch strings.ToLower(state) {
+	case StateRunning, "restarting":
+		return StateRunning
+	case StatePaused:
+		return StatePaused
+	case StateCreated, "configured", "initialized":
+		return StateCreated
+	case StateExited, "stopped", "dead", "removing":
+		return StateExited
+	default:
+		return StateUnknown
+	}
+}
+
+// IsSandboxRuntime returns true if the runtime of the container is one of the SandboxRuntimes
+func IsSandboxRuntime(runtime string) bool {
+	runtime = strings.ToLower(runtime)
+	for _, keyword := range SandboxRuntimes {
+		if strings.Contains(runtime, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRemoteEndpoint returns true if the endpoint connects to the runtime on another host
+func IsRemoteEndpoint(endpoint string) bool {
+	for _, scheme := range []string{"tcp://", "http://", "https://", "ssh://"} {
+		if strings.HasPrefix(strings.ToLower(endpoint), scheme) {
+			return true
+		}
+	}
+	return false
 }
 
 func GetChaosBladeImageRef(repo, version string) string {
@@ -24,22 +24,35 @@ import (
 	"os"
 	"os/exec"
 	"path"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
 )
 
+// CheckHostProcess returns an error if the pid is not a process of the local host,
+// the namespaces of the container process are entered by nsexec through /proc/<pid>
+func CheckHostProcess(pid int32) error {
+	if pid <= 0 {
+		return fmt.Errorf("the pid %d of the container process is invalid", pid)
+	}
+	if _, err := os.Stat(path.Join("/proc", strconv.Itoa(int(pid)), "ns")); err != nil {
+		return fmt.Errorf("the container process %d is not found on the local host, %v", pid, err)
+	}
+	return nil
+}
+
 func CopyToContainer(ctx context.Context, pid uint32, srcFile, dstPath, extractDirName string, override bool) error {
-	args := fmt.Sprintf("-t %d -p -m -- /bin/sh -c", pid)
-	argsArray := strings.Split(args, " ")
+	nsArgs := []string{"-t", strconv.FormatUint(uint64(pid), 10), "-p", "-m", "--"}
 	nsbin := path.Join(util.GetProgramPath(), "bin", spec.NSExecBin)
 
-	command := fmt.Sprintf("cat > %s", path.Join(dstPath, path.Base(srcFile)))
-	log.Infof(ctx, "run copy cmd: %s %s %s", nsbin, args, command)
+	dstFile := path.Join(dstPath, path.Base(srcFile))
+	command := "cat > " + ShellQuote(dstFile)
+	log.Infof(ctx, "run copy cmd: %s %q %s", nsbin, nsArgs, command)
 
-	cmd := exec.Command(nsbin, append(argsArray, command)...)
+	cmd := exec.Command(nsbin, append(nsArgs, "/bin/sh", "-c", command)...)
 
 	var outMsg bytes.Buffer
 	var errMsg bytes.Buffer
@@ -63,9 +76,9 @@ func CopyToContainer(ctx context.Context, pid uint32, srcFile, dstPath, extractD
 	}
 
 	// tar -zxf
-	command = fmt.Sprintf("-t %d -p -m -- tar -zxf %s -C %s", pid, path.Join(dstPath, path.Base(srcFile)), dstPath)
-	log.Infof(ctx, "run tar cmd: %s %s", nsbin, command)
-	cmd = exec.Command(nsbin, strings.Split(command, " ")...)
+	tarArgs := append(append([]string{}, nsArgs...), "tar", "-zxf", dstFile, "-C", dstPath)
+	log.Infof(ctx, "run tar cmd: %s %q", nsbin, tarArgs)
+	cmd = exec.Command(nsbin, tarArgs...)
 	//
 	var outMsg2 bytes.Buffer
 	var errMsg2 bytes.Buffer
@@ -84,52 +97,33 @@ func CopyToContainer(ctx context.Context, pid uint32, srcFile, dstPath, extractD
 	return nil
 }
 
-func ExecContainer(ctx context.Context, pid int32, command string) (output string, err error) {
-	args := fmt.Sprintf("-t %d -p -m -n -- /bin/sh -c", pid)
-	argsArray := strings.Split(args, " ")
+// ExecContainer executes the command by /bin/sh -c in the namespaces of the container process,
+// the arguments in the command must be quoted by ShellQuote
+func ExecContainer(ctx context.Context, pid int32, command string) (ExecResult, error) {
+	args := []string{"-t", strconv.Itoa(int(pid)), "-p", "-m", "-n", "--", "/bin/sh", "-c", command}
 	nsbin := path.Join(util.GetProgramPath(), "bin", spec.NSExecBin)
 
-	log.Infof(ctx, "exec container cmd: %s %s %s", nsbin, args, command)
-
-	cmd := exec.Command(nsbin, append(argsArray, command)...)
+	log.Infof(ctx, "exec container cmd: %s %q", nsbin, args)
 
+	cmd := exec.Command(nsbin, args...)
 	var outMsg bytes.Buffer
 	var errMsg bytes.Buffer
 	cmd.Stdout = &outMsg
 	cmd.Stderr = &errMsg
-	err = cmd.Run()
-
-	log.Debugf(ctx, "Command Result, output: %s, errMsg: %s, err: %v", outMsg.String(), errMsg.String(), err)
-
-	// 优先检查 stdout 中是否有 JSON 格式的响应
-	if outMsg.Len() > 0 {
-		outStr := strings.TrimSpace(outMsg.String())
-		// 如果 stdout 包含 JSON 格式的响应，优先返回
-		if strings.HasPrefix(outStr, "{") && strings.HasSuffix(outStr, "}") {
-			return outStr, nil
-		}
+	start := time.Now()
+	err := cmd.Run()
+	result := ExecResult{
+		Stdout:   outMsg.String(),
+		Stderr:   errMsg.String(),
+		Duration: time.Since(start),
 	}
-
-	// 检查 stderr 中的内容
-	if errMsg.Len() > 0 {
-		errStr := strings.TrimSpace(errMsg.String())
-		// 如果 stderr 包含 JSON 格式的响应，返回它
-		if strings.HasPrefix(errStr, "{") && strings.HasSuffix(errStr, "}") {
-			return errStr, nil
-		}
-		// 如果 stderr 不包含 JSON，但有内容，也返回
-		return errStr, nil
-	}
-
-	// 如果 stdout 有内容但不是 JSON 格式，返回它
-	if outMsg.Len() > 0 {
-		return strings.TrimSpace(outMsg.String()), nil
-	}
-
-	// 如果命令执行失败且没有输出，返回执行错误
+	log.Debugf(ctx, "Command Result, output: %s, errMsg: %s, err: %v", result.Stdout, result.Stderr, err)
 	if err != nil {
-		return "", err
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return result, err
+		}
+		result.ExitCode = exitErr.ExitCode()
 	}
-
-	return "", nil
+	return result, nil
 }
@@ -38,6 +38,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -49,6 +50,7 @@ import (
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
 	"github.com/containerd/containerd"
 	tasksv1 "github.com/containerd/containerd/api/services/tasks/v1"
+	tasktypes "github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/cio"
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/errdefs"
@@ -78,11 +80,17 @@ const (
 	DefaultSnapshotter = "overlayfs"
 
 	DefaultContainerdNS = "k8s.io"
+	MobyNS              = "moby"
+	DefaultNS           = "default"
 
 	NetworkNsType = "network"
+
+	// NerdctlNameLabel is the label of the container name which is created by nerdctl
+	NerdctlNameLabel = "nerdctl/name"
 )
 
-var cli *Client
+// PreferredNamespaces are searched first in order if the namespace is not specified
+var PreferredNamespaces = []string{DefaultContainerdNS, MobyNS, DefaultNS}
 
 type Client struct {
 	cclient *containerd.Client
@@ -90,41 +98,208 @@ type Client struct {
 	Ctx    context.Context
 	Cancel context.CancelFunc
 	connMu sync.Mutex
+
+	// searchAll is true if the namespace is not specified, all namespaces are searched for the container
+	searchAll bool
+	// containerNamespaces records the namespace of the found containers
+	containerNamespaces map[string]string
+	nsMu                sync.RWMutex
+
+	// key is the key of the client in the pool, the client is evicted by it if the connection is broken
+	key container.ClientKey
 }
 
+// NewClient returns the containerd client, all namespaces are searched for the container if the namespace is empty
 func NewClient(endpoint, namespace string) (*Client, error) {
-	if cli != nil {
-		if ok, _ := cli.cclient.IsServing(cli.Ctx); ok {
-			return cli, nil
+	if endpoint == "" {
+		endpoint = DefaultUinxAddress
+	}
+	key := container.ClientKey{Runtime: container.ContainerdRuntime, Endpoint: endpoint, Namespace: namespace}
+	cli, err := container.DefaultClientPool.Get(key, func() (container.PooledClient, error) {
+		searchAll := namespace == ""
+		defaultNamespace := namespace
+		if searchAll {
+			defaultNamespace = DefaultContainerdNS
 		}
+		cclient, err := containerd.New(endpoint, containerd.WithDefaultNamespace(defaultNamespace))
+		if err != nil {
+			return nil, err
+		}
+		var (
+			ctx    = context.Background()
+			cancel context.CancelFunc
+		)
+		ctx = namespaces.WithNamespace(ctx, defaultNamespace)
+		ctx, cancel = context.WithCancel(ctx)
+		return &Client{
+			cclient:             cclient,
+			connMu:              sync.Mutex{},
+			Ctx:                 ctx,
+			Cancel:              cancel,
+			searchAll:           searchAll,
+			containerNamespaces: make(map[string]string),
+			key:                 key,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return cli.(*Client), nil
+}
 
-	if endpoint == "" {
-		endpoint = DefaultUinxAddress
+// Ping checks the containerd is serving
+func (c *Client) Ping(ctx context.Context) error {
+	ok, err := c.cclient.IsServing(ctx)
+	if err != nil {
+		return err
 	}
-	if namespace == "" {
-		namespace = DefaultContainerdNS
+	if !ok {
+		return errors.New("containerd is not serving")
 	}
-	cclient, err := containerd.New(endpoint, containerd.WithDefaultNamespace(namespace))
+	return nil
+}
+
+// Close cancels the context of the client and closes the connection to containerd
+func (c *Client) Close() error {
+	c.Cancel()
+	return c.cclient.Close()
+}
+
+// searchNamespaces returns the namespaces to search, the preferred namespaces are in the front
+func (c *Client) searchNamespaces(ctx context.Context) ([]string, error) {
+	defaultNamespace, _ := namespaces.Namespace(c.Ctx)
+	if !c.searchAll {
+		return []string{defaultNamespace}, nil
+	}
+	nsList, err := c.cclient.NamespaceService().List(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var (
-		ctx    = context.Background()
-		cancel context.CancelFunc
-	)
-	ctx = namespaces.WithNamespace(ctx, namespace)
-	ctx, cancel = context.WithCancel(ctx)
-	cli = &Client{
-		cclient: cclient,
-		connMu:  sync.Mutex{},
-		Ctx:     ctx,
-		Cancel:  cancel,
-	}
-	return cli, nil
+	exists := make(map[string]bool, len(nsList))
+	for _, ns := range nsList {
+		exists[ns] = true
+	}
+	result := make([]string, 0, len(nsList))
+	for _, ns := range PreferredNamespaces {
+		if exists[ns] {
+			result = append(result, ns)
+			delete(exists, ns)
+		}
+	}
+	others := make([]string, 0, len(exists))
+	for _, ns := range nsList {
+		if exists[ns] {
+			others = append(others, ns)
+		}
+	}
+	sort.Strings(others)
+	return append(result, others...), nil
+}
+
+// withContainerNamespace returns the context with the namespace of the container. The namespace pinned in the
+// context is used first, then the namespace where the container is found by this client. Otherwise the container
+// is searched in all namespaces, it fails if the id is found in more than one namespace
+func (c *Client) withContainerNamespace(ctx context.Context, containerId string) (context.Context, error) {
+	if ns := container.TargetNamespace(ctx); ns != "" {
+		return namespaces.WithNamespace(ctx, ns), nil
+	}
+	c.nsMu.RLock()
+	ns, ok := c.containerNamespaces[containerId]
+	c.nsMu.RUnlock()
+	if !ok {
+		ns, _ = namespaces.Namespace(c.Ctx)
+		if c.searchAll {
+			// the container is not looked up by this client, find it from all namespaces
+			info, err, code := c.GetContainerById(ctx, containerId)
+			if err != nil && code != spec.ParameterInvalidDockContainerId.Code {
+				return ctx, err
+			}
+			if err == nil {
+				ns = info.Namespace
+			}
+		}
+	}
+	return namespaces.WithNamespace(ctx, ns), nil
+}
+
+func (c *Client) recordNamespace(containerId, namespace string) {
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+	c.containerNamespaces[containerId] = namespace
+}
+
+// recordNamespaces records the namespaces of the listed containers, the ids found in more than one namespace are
+// not recorded, they must be pinned by the caller
+func (c *Client) recordNamespaces(containers []container.ContainerInfo) {
+	found := make(map[string][]string, len(containers))
+	for _, info := range containers {
+		found[info.ContainerId] = append(found[info.ContainerId], info.Namespace)
+	}
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+	for id, nsList := range found {
+		if len(nsList) == 1 {
+			c.containerNamespaces[id] = nsList[0]
+		} else {
+			delete(c.containerNamespaces, id)
+		}
+	}
+}
+
+// listContainers returns the containers matched the filters in all namespaces to search
+func (c *Client) listContainers(ctx context.Context, filters ...string) ([]container.ContainerInfo, error) {
+	nsList, err := c.searchNamespaces(ctx)
+	if err != nil {
+		container.EvictOnConnectionError(c.key, c, err)
+		return nil, err
+	}
+	return c.listContainersIn(ctx, nsList, filters...)
+}
+
+// listContainersIn returns the containers matched the filters in the namespaces
+func (c *Client) listContainersIn(ctx context.Context, nsList []string, filters ...string) ([]container.ContainerInfo, error) {
+	result := make([]container.ContainerInfo, 0)
+	for _, ns := range nsList {
+		containerDetails, err := c.cclient.ContainerService().List(namespaces.WithNamespace(ctx, ns), filters...)
+		if err != nil {
+			container.EvictOnConnectionError(c.key, c, err)
+			return nil, err
+		}
+		for _, containerDetail := range containerDetails {
+			info := convertContainerInfo(containerDetail)
+			info.Namespace = ns
+			result = append(result, info)
+		}
+	}
+	return result, nil
+}
+
+// uniqueContainer returns the only container, or an error if the containers are found in different namespaces
+func (c *Client) uniqueContainer(containers []container.ContainerInfo, flag, value string, notFound spec.CodeType) (container.ContainerInfo, error, int32) {
+	if len(containers) == 0 {
+		if notFound == spec.ParameterInvalid {
+			return container.ContainerInfo{}, errors.New(spec.ParameterInvalid.Sprintf(flag, value, "can not find container")), notFound.Code
+		}
+		return container.ContainerInfo{}, errors.New(notFound.Sprintf(flag)), notFound.Code
+	}
+	if len(containers) > 1 {
+		matches := make([]string, 0, len(containers))
+		for _, ctr := range containers {
+			matches = append(matches, fmt.Sprintf("%s/%s", ctr.Namespace, ctr.ContainerId))
+		}
+		return container.ContainerInfo{}, errors.New(spec.ParameterInvalid.Sprintf(flag, value,
+				fmt.Sprintf("multiple containers are found: %s, please specify the container-namespace flag", strings.Join(matches, ", ")))),
+			spec.ParameterInvalid.Code
+	}
+	c.recordNamespace(containers[0].ContainerId, containers[0].Namespace)
+	return containers[0], nil, spec.OK.Code
 }
 
 func (c *Client) GetPidById(ctx context.Context, containerId string) (int32, error, int32) {
+	ctx, err := c.withContainerNamespace(ctx, containerId)
+	if err != nil {
+		return -1, err, spec.ParameterInvalid.Code
+	}
 	container, err := c.cclient.LoadContainer(ctx, containerId)
 	if err != nil {
 		return -1, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
@@ -142,23 +317,35 @@ func (c *Client) GetContainerById(ctx context.Context, containerId string) (cont
 		return container.ContainerInfo{}, errors.New("containerd client is not available"), spec.ContainerExecFailed.Code
 	}
 
-	containerDetail, err := c.cclient.ContainerService().Get(c.Ctx, containerId)
+	nsList, err := c.searchNamespaces(ctx)
 	if err != nil {
 		return container.ContainerInfo{}, err, spec.ContainerExecFailed.Code
 	}
-
-	return convertContainerInfo(containerDetail), nil, spec.OK.Code
+	containers := make([]container.ContainerInfo, 0)
+	for _, ns := range nsList {
+		containerDetail, err := c.cclient.ContainerService().Get(namespaces.WithNamespace(ctx, ns), containerId)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return container.ContainerInfo{}, err, spec.ContainerExecFailed.Code
+		}
+		info := convertContainerInfo(containerDetail)
+		info.Namespace = ns
+		containers = append(containers, info)
+	}
+	return c.uniqueContainer(containers, "container-id", containerId, spec.ParameterInvalidDockContainerId)
 }
 
+// GetContainerByName returns the container by the name, containerd has no container name, so the name is
+// the nerdctl name label or the kubernetes container name label
 func (c *Client) GetContainerByName(ctx context.Context, containerName string) (container.ContainerInfo, error, int32) {
-	// containerd have not name. so maybe it is not usefull
-	filters := []string{fmt.Sprintf("runtime,name==%s", containerName)}
-	containerDetails, err := c.cclient.ContainerService().List(c.Ctx, filters...)
+	containers, err, code := c.GetContainers(ctx, container.ContainerFilter{ContainerName: containerName})
 	if err != nil {
-		return container.ContainerInfo{}, err, spec.ContainerExecFailed.Code
+		return container.ContainerInfo{}, err, code
 	}
 
-	return convertContainerInfo(containerDetails[0]), nil, spec.OK.Code
+	return c.uniqueContainer(containers, "container-name", containerName, spec.ParameterInvalidDockContainerName)
 }
 
 func (c *Client) GetContainerByLabelSelector(labels map[string]string) (container.ContainerInfo, error, int32) {
@@ -168,25 +355,98 @@ func (c *Client) GetContainerByLabelSelector(labels map[string]string) (containe
 		filters = append(filters, fmt.Sprintf(`labels."%s"==%s`, k, v))
 	}
 
-	containerDetails, err := c.cclient.ContainerService().List(c.Ctx, strings.Join(filters, ","))
+	containers, err := c.listContainers(c.Ctx, strings.Join(filters, ","))
 	if err != nil {
 		return container.ContainerInfo{}, err, spec.ContainerExecFailed.Code
 	}
 
-	return convertContainerInfo(containerDetails[0]), nil, spec.OK.Code
+	return c.uniqueContainer(containers, "container-label-selector", fmt.Sprintf("%v", labels), spec.ParameterInvalid)
 }
 
 func convertContainerInfo(containerDetail containers.Container) container.ContainerInfo {
+	containerName := containerDetail.Labels[NerdctlNameLabel]
+	if containerName == "" {
+		containerName = containerDetail.Labels[container.KubernetesContainerNameLabel]
+	}
 	return container.ContainerInfo{
 		ContainerId:   containerDetail.ID,
-		ContainerName: containerDetail.Labels["io.kubernetes.container.name"],
+		ContainerName: containerName,
 		// Env:             spec.Process.Env,
-		Labels: containerDetail.Labels,
-		Spec:   containerDetail.Spec,
+		Labels:  containerDetail.Labels,
+		Spec:    containerDetail.Spec,
+		Image:   containerDetail.Image,
+		Created: containerDetail.CreatedAt,
+		Runtime: container.ContainerdRuntime,
+	}
+}
+
+// GetContainers returns all the containers matched the filter in all namespaces to search
+func (c *Client) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	fieldFilters := make([]string, 0, len(filter.Labels)+1)
+	for k, v := range filter.Labels {
+		fieldFilters = append(fieldFilters, fmt.Sprintf(`labels.%q==%q`, k, v))
+	}
+	if filter.Image != "" && !container.IsImageRegexp(filter.Image) {
+		// containerd records the fully qualified image reference
+		fieldFilters = append(fieldFilters, fmt.Sprintf(`image==%q`, container.NormalizeImage(filter.Image)))
+	}
+	var containers []container.ContainerInfo
+	var err error
+	if filter.Namespace != "" {
+		// the namespace of the container is known, such as the containers recorded in the experiment state
+		containers, err = c.listContainersIn(ctx, []string{filter.Namespace}, strings.Join(fieldFilters, ","))
+	} else {
+		containers, err = c.listContainers(ctx, strings.Join(fieldFilters, ","))
+	}
+	if err != nil {
+		return nil, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
+	}
+	result := make([]container.ContainerInfo, 0, len(containers))
+	for _, info := range containers {
+		if !filter.Match(info) {
+			continue
+		}
+		c.fillTaskState(ctx, &info)
+		result = append(result, info)
 	}
+	c.recordNamespaces(result)
+	return result, nil, spec.OK.Code
+}
+
+// fillTaskState sets the state and the pid of the container by its task
+func (c *Client) fillTaskState(ctx context.Context, info *container.ContainerInfo) {
+	resp, err := c.cclient.TaskService().Get(namespaces.WithNamespace(ctx, info.Namespace), &tasksv1.GetRequest{
+		ContainerID: info.ContainerId,
+	})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// the task of the container is not created or has been deleted
+			info.State = container.StateCreated
+		} else {
+			info.State = container.StateUnknown
+		}
+		return
+	}
+	switch resp.Process.Status {
+	case tasktypes.Status_RUNNING, tasktypes.Status_PAUSING:
+		info.State = container.StateRunning
+	case tasktypes.Status_PAUSED:
+		info.State = container.StatePaused
+	case tasktypes.Status_CREATED:
+		info.State = container.StateCreated
+	case tasktypes.Status_STOPPED:
+		info.State = container.StateExited
+	default:
+		info.State = container.StateUnknown
+	}
+	info.Pid = int32(resp.Process.Pid)
 }
 
 func (c *Client) RemoveContainer(ctx context.Context, containerId string, _ bool) error {
+	ctx, err := c.withContainerNamespace(ctx, containerId)
+	if err != nil {
+		return err
+	}
 	if _, err := c.cclient.TaskService().Kill(ctx, &tasksv1.KillRequest{
 		ContainerID: containerId,
 		Signal:      uint32(syscall.SIGKILL),
@@ -211,13 +471,23 @@ func (c *Client) RemoveContainer(ctx context.Context, containerId string, _ bool
 	return nil
 }
 
+// CopyToContainer copies a tar file to the dstPath by nsexec, the exec api of the task is used if nsexec is not
+// viable for the container
 func (c *Client) CopyToContainer(ctx context.Context, containerId, srcFile, dstPath, extractDirName string, override bool) error {
-	containerDetail, err := c.cclient.LoadContainer(c.Ctx, containerId)
+	if err := c.CheckNSExec(ctx, containerId); err != nil {
+		log.Infof(ctx, "copy the file to the container %s by the task exec, %v", containerId, err)
+		return c.copyToContainerByAPI(ctx, containerId, srcFile, dstPath)
+	}
+	nsCtx, err := c.withContainerNamespace(ctx, containerId)
+	if err != nil {
+		return err
+	}
+	containerDetail, err := c.cclient.LoadContainer(nsCtx, containerId)
 	if err != nil {
 		return err
 	}
 
-	task, err := containerDetail.Task(c.Ctx, nil)
+	task, err := containerDetail.Task(nsCtx, nil)
 	if err != nil {
 		return err
 	}
@@ -227,10 +497,16 @@ func (c *Client) CopyToContainer(ctx context.Context, containerId, srcFile, dstP
 	return container.CopyToContainer(ctx, processId, srcFile, dstPath, extractDirName, override)
 }
 
-func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (output string, err error) {
+// ExecContainer executes the command in the namespaces of the container process by nsexec,
+// the exec api of the task is used if nsexec is not viable for the container
+func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	if err := c.CheckNSExec(ctx, containerId); err != nil {
+		log.Infof(ctx, "execute the command in the container %s by the task exec, %v", containerId, err)
+		return c.execContainerByAPI(ctx, containerId, command, nil)
+	}
 	id, err, _ := c.GetPidById(ctx, containerId)
 	if err != nil {
-		return "", err
+		return container.ExecResult{}, err
 	}
 	return container.ExecContainer(ctx, id, command)
 }
@@ -239,8 +515,13 @@ func (c *Client) ExecContainer(ctx context.Context, containerId, command string)
 func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Config, hostConfig *containertype.HostConfig,
 	networkConfig *network.NetworkingConfig, containerName string, removed bool, timeout time.Duration,
 	command string, containerInfo container.ContainerInfo,
-) (containerId string, output string, err error, code int32) {
+) (containerId string, result container.ExecResult, err error, code int32) {
 	snapshotter := DefaultSnapshotter
+	// create the container in the namespace of the target container
+	nsCtx := c.Ctx
+	if containerInfo.Namespace != "" {
+		nsCtx = namespaces.WithNamespace(c.Ctx, containerInfo.Namespace)
+	}
 
 	// 1. get container network namespace path
 	var specInfo specs.Spec
@@ -253,27 +534,27 @@ func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Con
 		}
 	}
 	if networkNsPath == "" {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf("target container network namespace path is nil")), spec.CreateContainerFailed.Code
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf("target container network namespace path is nil")), spec.CreateContainerFailed.Code
 	}
 
 	// 2. pull image befor create container
-	if _, err := c.cclient.Pull(c.Ctx, config.Image, containerd.WithPullUnpack, containerd.WithPullSnapshotter(snapshotter)); err != nil {
-		return "", "", errors.New(spec.ImagePullFailed.Sprintf(config.Image, err.Error())), spec.ImagePullFailed.Code
+	if _, err := c.cclient.Pull(nsCtx, config.Image, containerd.WithPullUnpack, containerd.WithPullSnapshotter(snapshotter)); err != nil {
+		return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, err.Error())), spec.ImagePullFailed.Code
 	}
 
-	images, err := c.cclient.GetImage(c.Ctx, config.Image)
+	images, err := c.cclient.GetImage(nsCtx, config.Image)
 	if err != nil {
-		return "", "", errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Get image failed, %s", err.Error()))), spec.ImagePullFailed.Code
+		return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Get image failed, %s", err.Error()))), spec.ImagePullFailed.Code
 	}
 
-	unpacked, err := images.IsUnpacked(c.Ctx, snapshotter)
+	unpacked, err := images.IsUnpacked(nsCtx, snapshotter)
 	if err != nil {
-		return "", "", errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Get isUnpacked failed: %v", err))), spec.ImagePullFailed.Code
+		return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Get isUnpacked failed: %v", err))), spec.ImagePullFailed.Code
 	}
 
 	if !unpacked {
-		if err := images.Unpack(c.Ctx, snapshotter); err != nil {
-			return "", "", errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Unpack failed: %v", err))), spec.ImagePullFailed.Code
+		if err := images.Unpack(nsCtx, snapshotter); err != nil {
+			return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, fmt.Sprintf("Unpack failed: %v", err))), spec.ImagePullFailed.Code
 		}
 	}
 
@@ -302,7 +583,7 @@ func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Con
 
 	runtimeOpts, err := getRuntimeOptions()
 	if err != nil {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Get runtime options failed: %v", err))), spec.CreateContainerFailed.Code
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Get runtime options failed: %v", err))), spec.CreateContainerFailed.Code
 	}
 	cOpts = append(cOpts, containerd.WithRuntime(DefaultRuntime, runtimeOpts))
 	opts = append(opts, oci.WithAnnotations(config.Labels))
@@ -313,59 +594,69 @@ func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Con
 
 	// 5. create new container
 	var cntr containerd.Container
-	if cntr, err = c.cclient.NewContainer(c.Ctx, containerId, cOpts...); err != nil {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf(err)), spec.CreateContainerFailed.Code
+	if cntr, err = c.cclient.NewContainer(nsCtx, containerId, cOpts...); err != nil {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(err)), spec.CreateContainerFailed.Code
 	}
 
 	defer func() {
 		deferCtx, deferCancel := ctrdutil.DeferContext()
 		defer deferCancel()
 
+		if ns, err := namespaces.NamespaceRequired(nsCtx); err == nil {
+			deferCtx = namespaces.WithNamespace(deferCtx, ns)
+		}
 		if err := cntr.Delete(deferCtx, containerd.WithSnapshotCleanup); err != nil {
 			log.Warnf(ctx, "Failed to delete containerd container %v, err: %v", containerId, err)
 		}
 	}()
 
 	// 6. start a container that has been created
+	if ns, err := namespaces.NamespaceRequired(nsCtx); err == nil {
+		c.recordNamespace(containerId, ns)
+	}
 	task, err := c.NewTask(config.Image, cntr)
 	if err != nil {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("New task, %s", err.Error()))), spec.CreateContainerFailed.Code
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("New task, %s", err.Error()))), spec.CreateContainerFailed.Code
 	}
 	defer func() {
-		if _, err = task.Delete(c.Ctx); err != nil {
+		if _, err = task.Delete(nsCtx); err != nil {
 			log.Warnf(ctx, "Failed to delete containerd task %v, err: %v", containerId, err)
 		}
 	}()
 
-	tStatus, err := task.Wait(c.Ctx)
+	tStatus, err := task.Wait(nsCtx)
 	if err != nil {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Task wait, %s", err.Error()))), spec.CreateContainerFailed.Code
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Task wait, %s", err.Error()))), spec.CreateContainerFailed.Code
 	}
 
-	if err = task.Start(c.Ctx); err != nil {
-		return "", "", errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Task start, %s", err.Error()))), spec.CreateContainerFailed.Code
+	if err = task.Start(nsCtx); err != nil {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("Task start, %s", err.Error()))), spec.CreateContainerFailed.Code
 	}
 
 	// 7. exec command in new container
-	output, err = c.ExecContainer(ctx, containerId, command)
+	result, err = c.ExecContainer(ctx, containerId, command)
 	if err != nil {
-		return containerId, output, errors.New(spec.ContainerExecFailed.Sprintf(command, err)), spec.ContainerExecFailed.Code
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf(command, err)), spec.ContainerExecFailed.Code
 	}
 
-	if err := task.Kill(c.Ctx, syscall.SIGKILL); err != nil {
-		return containerId, output, errors.New(spec.ContainerExecFailed.Sprintf(command, err)), spec.ContainerExecFailed.Code
+	if err := task.Kill(nsCtx, syscall.SIGKILL); err != nil {
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf(command, err)), spec.ContainerExecFailed.Code
 	}
 
 	<-tStatus
 
-	return cntr.ID(), output, nil, spec.OK.Code
+	return cntr.ID(), result, nil, spec.OK.Code
 }
 
 func (c *Client) NewTask(imageRef string, cntr containerd.Container) (containerd.Task, error) {
 	var tOpts []containerd.NewTaskOpts
 
 	ioCreator := cio.NullIO
-	task, err := cntr.NewTask(c.Ctx, ioCreator, tOpts...)
+	nsCtx, err := c.withContainerNamespace(c.Ctx, cntr.ID())
+	if err != nil {
+		return nil, err
+	}
+	task, err := cntr.NewTask(nsCtx, ioCreator, tOpts...)
 	if err != nil {
 		return nil, err
 	}
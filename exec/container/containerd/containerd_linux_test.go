@@ -0,0 +1,231 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	containersapi "github.com/containerd/containerd/api/services/containers/v1"
+	namespacesapi "github.com/containerd/containerd/api/services/namespaces/v1"
+	tasksv1 "github.com/containerd/containerd/api/services/tasks/v1"
+	tasktypes "github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeContainerd serves the containers and the tasks of the namespaces, the pid of a task is keyed by namespace/id
+type fakeContainerd struct {
+	containers map[string][]string
+	pids       map[string]uint32
+
+	mu     sync.Mutex
+	killed []string
+}
+
+func requestNamespace(ctx context.Context) string {
+	ns, _ := namespaces.Namespace(ctx)
+	return ns
+}
+
+type fakeNamespaces struct {
+	namespacesapi.UnimplementedNamespacesServer
+	*fakeContainerd
+}
+
+func (f fakeNamespaces) List(ctx context.Context, _ *namespacesapi.ListNamespacesRequest) (*namespacesapi.ListNamespacesResponse, error) {
+	resp := &namespacesapi.ListNamespacesResponse{}
+	for ns := range f.containers {
+		resp.Namespaces = append(resp.Namespaces, &namespacesapi.Namespace{Name: ns})
+	}
+	return resp, nil
+}
+
+func (f fakeNamespaces) Get(ctx context.Context, req *namespacesapi.GetNamespaceRequest) (*namespacesapi.GetNamespaceResponse, error) {
+	return &namespacesapi.GetNamespaceResponse{Namespace: &namespacesapi.Namespace{Name: req.Name}}, nil
+}
+
+type fakeContainers struct {
+	containersapi.UnimplementedContainersServer
+	*fakeContainerd
+}
+
+func (f fakeContainers) List(ctx context.Context, _ *containersapi.ListContainersRequest) (*containersapi.ListContainersResponse, error) {
+	resp := &containersapi.ListContainersResponse{}
+	for _, id := range f.containers[requestNamespace(ctx)] {
+		resp.Containers = append(resp.Containers, &containersapi.Container{ID: id})
+	}
+	return resp, nil
+}
+
+func (f fakeContainers) Get(ctx context.Context, req *containersapi.GetContainerRequest) (*containersapi.GetContainerResponse, error) {
+	for _, id := range f.containers[requestNamespace(ctx)] {
+		if id == req.ID {
+			return &containersapi.GetContainerResponse{Container: &containersapi.Container{ID: id}}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "container %q: not found", req.ID)
+}
+
+func (f fakeContainers) Delete(ctx context.Context, req *containersapi.DeleteContainerRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+type fakeTasks struct {
+	tasksv1.UnimplementedTasksServer
+	*fakeContainerd
+}
+
+func (f fakeTasks) Get(ctx context.Context, req *tasksv1.GetRequest) (*tasksv1.GetResponse, error) {
+	pid, ok := f.pids[requestNamespace(ctx)+"/"+req.ContainerID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "task %q: not found", req.ContainerID)
+	}
+	return &tasksv1.GetResponse{Process: &tasktypes.Process{ID: req.ContainerID, Pid: pid, Status: tasktypes.Status_RUNNING}}, nil
+}
+
+func (f fakeTasks) Kill(ctx context.Context, req *tasksv1.KillRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.killed = append(f.killed, requestNamespace(ctx)+"/"+req.ContainerID)
+	return &emptypb.Empty{}, nil
+}
+
+// startFakeContainerd serves the fake containerd and returns the client which searches all namespaces
+func startFakeContainerd(t *testing.T, fake *fakeContainerd) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "containerd.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen %s: %v", socket, err)
+	}
+	server := grpc.NewServer()
+	namespacesapi.RegisterNamespacesServer(server, fakeNamespaces{fakeContainerd: fake})
+	containersapi.RegisterContainersServer(server, fakeContainers{fakeContainerd: fake})
+	tasksv1.RegisterTasksServer(server, fakeTasks{fakeContainerd: fake})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cli, err := NewClient(socket, "")
+	if err != nil {
+		t.Fatalf("NewClient(%s): %v", socket, err)
+	}
+	t.Cleanup(func() { container.DefaultClientPool.CloseAll() })
+	return cli
+}
+
+// testContainerd has the container abc in both k8s.io and default, and the container xyz only in default
+func testContainerd() *fakeContainerd {
+	return &fakeContainerd{
+		containers: map[string][]string{
+			DefaultContainerdNS: {"abc"},
+			DefaultNS:           {"abc", "xyz"},
+		},
+		pids: map[string]uint32{
+			DefaultContainerdNS + "/abc": 100,
+			DefaultNS + "/abc":           200,
+			DefaultNS + "/xyz":           300,
+		},
+	}
+}
+
+func TestClientGetContainersInNamespaces(t *testing.T) {
+	cli := startFakeContainerd(t, testContainerd())
+	tests := []struct {
+		name   string
+		filter container.ContainerFilter
+		want   []string
+	}{
+		{"all namespaces", container.ContainerFilter{}, []string{"k8s.io/abc/100", "default/abc/200", "default/xyz/300"}},
+		{"by id", container.ContainerFilter{ContainerId: "abc"}, []string{"k8s.io/abc/100", "default/abc/200"}},
+		{"by namespace", container.ContainerFilter{ContainerId: "abc", Namespace: DefaultNS}, []string{"default/abc/200"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err, _ := cli.GetContainers(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetContainers() err = %v", err)
+			}
+			got := make([]string, 0, len(containers))
+			for _, ctr := range containers {
+				got = append(got, fmt.Sprintf("%s/%s/%d", ctr.Namespace, ctr.ContainerId, ctr.Pid))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("GetContainers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetPidByIdNamespace(t *testing.T) {
+	cli := startFakeContainerd(t, testContainerd())
+	// the ambiguous id must not be recorded by listing
+	if _, err, _ := cli.GetContainers(context.Background(), container.ContainerFilter{}); err != nil {
+		t.Fatalf("GetContainers() err = %v", err)
+	}
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		containerId string
+		want        int32
+		wantErr     string
+	}{
+		{"unique id", context.Background(), "xyz", 300, ""},
+		{"ambiguous id", context.Background(), "abc", -1, "k8s.io/abc, default/abc"},
+		{"pinned to k8s.io", container.WithTargetNamespace(context.Background(), DefaultContainerdNS), "abc", 100, ""},
+		{"pinned to default", container.WithTargetNamespace(context.Background(), DefaultNS), "abc", 200, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pid, err, _ := cli.GetPidById(tt.ctx, tt.containerId)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GetPidById(%s) err = %v, want %s", tt.containerId, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || pid != tt.want {
+				t.Errorf("GetPidById(%s) = %d, %v, want %d", tt.containerId, pid, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientRemoveContainerPinned(t *testing.T) {
+	fake := testContainerd()
+	cli := startFakeContainerd(t, fake)
+	ctx := container.WithTargetNamespace(context.Background(), DefaultNS)
+	if err := cli.RemoveContainer(ctx, "abc", true); err != nil {
+		t.Fatalf("RemoveContainer() err = %v", err)
+	}
+	if strings.Join(fake.killed, ",") != "default/abc" {
+		t.Errorf("RemoveContainer() killed %v, want default/abc", fake.killed)
+	}
+	if err := cli.RemoveContainer(context.Background(), "abc", true); err == nil {
+		t.Error("RemoveContainer() of the ambiguous id succeeds")
+	}
+}
@@ -0,0 +1,183 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+	"github.com/containerd/containerd"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// CheckNSExec returns an error if the container runs in a sandbox runtime or its process is not found on the local host,
+// the result is cached in the context of the experiment
+func (c *Client) CheckNSExec(ctx context.Context, containerId string) error {
+	return container.CachedNSExecCheck(ctx, containerId, func() error {
+		return c.checkNSExec(ctx, containerId)
+	})
+}
+
+func (c *Client) checkNSExec(ctx context.Context, containerId string) error {
+	nsCtx, err := c.withContainerNamespace(ctx, containerId)
+	if err != nil {
+		return err
+	}
+	cntr, err := c.cclient.LoadContainer(nsCtx, containerId)
+	if err != nil {
+		return err
+	}
+	info, err := cntr.Info(nsCtx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return err
+	}
+	if container.IsSandboxRuntime(info.Runtime.Name) {
+		return fmt.Errorf("the container %s runs in the sandbox runtime %s", containerId, info.Runtime.Name)
+	}
+	task, err := cntr.Task(nsCtx, nil)
+	if err != nil {
+		return err
+	}
+	return container.CheckHostProcess(int32(task.Pid()))
+}
+
+// execContainerByAPI executes the command by /bin/sh -c in the container by the exec api of the task as root,
+// the stdin is written to the process if it is not nil. The output is transferred by the fifos of the direct io
+func (c *Client) execContainerByAPI(ctx context.Context, containerId, command string, stdin io.Reader) (container.ExecResult, error) {
+	nsCtx, err := c.withContainerNamespace(ctx, containerId)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	cntr, err := c.cclient.LoadContainer(nsCtx, containerId)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	task, err := cntr.Task(nsCtx, nil)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	s, err := cntr.Spec(nsCtx)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	if s.Process == nil {
+		return container.ExecResult{}, fmt.Errorf("the process spec of the container %s is empty", containerId)
+	}
+	pspec := *s.Process
+	pspec.Terminal = false
+	pspec.Args = []string{"/bin/sh", "-c", command}
+	pspec.User = specs.User{UID: 0, GID: 0}
+
+	execId := fmt.Sprintf("chaosblade-%s", util.GenerateContainerId()[:12])
+	log.Infof(ctx, "exec container cmd by task exec: %s, container: %s, exec id: %s", command, containerId, execId)
+	dio, err := newDirectIO(nsCtx, execId, false)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	defer dio.Delete()
+
+	start := time.Now()
+	process, err := task.Exec(nsCtx, execId, &pspec, dio.IOCreate)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	defer func() {
+		if _, err := process.Delete(nsCtx); err != nil {
+			log.Warnf(ctx, "Failed to delete the exec process %s of the container %s, err: %v", execId, containerId, err)
+		}
+	}()
+	statusC, err := process.Wait(nsCtx)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+
+	var wg sync.WaitGroup
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(outBuf, dio.Stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(errBuf, dio.Stderr)
+	}()
+
+	if err := process.Start(nsCtx); err != nil {
+		return container.ExecResult{}, err
+	}
+	if stdin != nil {
+		if _, err := io.Copy(dio.Stdin, stdin); err != nil {
+			process.Kill(nsCtx, syscall.SIGKILL)
+			<-statusC
+			return container.ExecResult{}, err
+		}
+	}
+	dio.Stdin.Close()
+	if err := process.CloseIO(nsCtx, containerd.WithStdinCloser); err != nil {
+		log.Warnf(ctx, "Failed to close the stdin of the exec process %s, err: %v", execId, err)
+	}
+
+	status := <-statusC
+	exitCode, _, err := status.Result()
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	wg.Wait()
+	result := container.ExecResult{
+		ExitCode: int(exitCode),
+		Stdout:   outBuf.String(),
+		Stderr:   errBuf.String(),
+		Duration: time.Since(start),
+	}
+	log.Debugf(ctx, "Command Result, output: %s, errMsg: %s, exitCode: %d", result.Stdout, result.Stderr, result.ExitCode)
+	return result, nil
+}
+
+// copyToContainerByAPI writes the tar file to the dstPath by the stdin of the exec process and extracts it
+func (c *Client) copyToContainerByAPI(ctx context.Context, containerId, srcFile, dstPath string) error {
+	file, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	dstFile := path.Join(dstPath, path.Base(srcFile))
+	commands := []string{
+		container.ShellJoin("mkdir", "-p", dstPath),
+		"cat > " + container.ShellQuote(dstFile),
+		container.ShellJoin("tar", "-zxf", dstFile, "-C", dstPath),
+	}
+	result, err := c.execContainerByAPI(ctx, containerId, strings.Join(commands, " && "), file)
+	if err != nil {
+		return err
+	}
+	return result.Err()
+}
@@ -15,3 +15,61 @@
  */
 
 package cri_o
+
+import (
+	"context"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/cri"
+)
+
+// DefaultEndpoint is the default unix socket address of cri-o
+const DefaultEndpoint = "unix:///var/run/crio/crio.sock"
+
+// Client is the cri-o client, cri-o only serves the kubernetes CRI api
+type Client struct {
+	*cri.Client
+}
+
+// NewClient returns the cri-o client, the default cri-o endpoint is used if the endpoint is empty
+func NewClient(endpoint string) (*Client, error) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	cli, err := cri.NewClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: cli}, nil
+}
+
+// GetContainers returns all the containers matched the filter
+func (c *Client) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	containers, err, code := c.Client.GetContainers(ctx, filter)
+	for idx := range containers {
+		containers[idx].Runtime = container.CrioRuntime
+	}
+	return containers, err, code
+}
+
+// GetContainerById returns the container whose id is prefixed by the containerId in any state
+func (c *Client) GetContainerById(ctx context.Context, containerId string) (container.ContainerInfo, error, int32) {
+	return withRuntime(c.Client.GetContainerById(ctx, containerId))
+}
+
+// GetContainerByName returns the container by the name in the container metadata
+func (c *Client) GetContainerByName(ctx context.Context, containerName string) (container.ContainerInfo, error, int32) {
+	return withRuntime(c.Client.GetContainerByName(ctx, containerName))
+}
+
+func (c *Client) GetContainerByLabelSelector(labels map[string]string) (container.ContainerInfo, error, int32) {
+	return withRuntime(c.Client.GetContainerByLabelSelector(labels))
+}
+
+// withRuntime marks the container found by the cri client as a cri-o container
+func withRuntime(info container.ContainerInfo, err error, code int32) (container.ContainerInfo, error, int32) {
+	if err == nil {
+		info.Runtime = container.CrioRuntime
+	}
+	return info, err, code
+}
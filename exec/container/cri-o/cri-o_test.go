@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cri_o
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeRuntimeService serves the exited containers of the CRI runtime service of cri-o
+type fakeRuntimeService struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+
+	containers []*runtimeapi.Container
+}
+
+func (s *fakeRuntimeService) Version(ctx context.Context, req *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{RuntimeName: "cri-o", RuntimeApiVersion: "v1"}, nil
+}
+
+func (s *fakeRuntimeService) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	return &runtimeapi.ListContainersResponse{Containers: s.containers}, nil
+}
+
+func startFakeRuntimeService(t *testing.T) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "crio.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen %s: %v", socket, err)
+	}
+	server := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(server, &fakeRuntimeService{
+		containers: []*runtimeapi.Container{{
+			Id:       "abc123",
+			Metadata: &runtimeapi.ContainerMetadata{Name: "nginx"},
+			State:    runtimeapi.ContainerState_CONTAINER_EXITED,
+		}},
+	})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cli, err := NewClient("unix://" + socket)
+	if err != nil {
+		t.Fatalf("NewClient(%s): %v", socket, err)
+	}
+	t.Cleanup(func() { container.DefaultClientPool.CloseAll() })
+	return cli
+}
+
+func TestClientRuntime(t *testing.T) {
+	cli := startFakeRuntimeService(t)
+	ctx := context.Background()
+	if err := cli.Ping(ctx); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+	containers, err, _ := cli.GetContainers(ctx, container.ContainerFilter{})
+	if err != nil || len(containers) != 1 {
+		t.Fatalf("GetContainers() = %v, %v, want one container", containers, err)
+	}
+	if containers[0].Runtime != container.CrioRuntime {
+		t.Errorf("GetContainers() runtime = %s, want %s", containers[0].Runtime, container.CrioRuntime)
+	}
+	lookups := map[string]func() (container.ContainerInfo, error, int32){
+		"GetContainerById":   func() (container.ContainerInfo, error, int32) { return cli.GetContainerById(ctx, "abc") },
+		"GetContainerByName": func() (container.ContainerInfo, error, int32) { return cli.GetContainerByName(ctx, "nginx") },
+	}
+	for name, lookup := range lookups {
+		info, err, _ := lookup()
+		if err != nil || info.ContainerId != "abc123" {
+			t.Errorf("%s() = %s, %v, want abc123", name, info.ContainerId, err)
+			continue
+		}
+		if info.Runtime != container.CrioRuntime {
+			t.Errorf("%s() runtime = %s, want %s", name, info.Runtime, container.CrioRuntime)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	containertype "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+const (
+	// RuntimeEndpointEnv is the environment variable of the runtime endpoint, the same as crictl
+	RuntimeEndpointEnv = "CONTAINER_RUNTIME_ENDPOINT"
+
+	connectionTimeout  = 2 * time.Second
+	maxMsgSize         = 16 * 1024 * 1024
+	defaultStopTimeout = 10
+)
+
+// DefaultEndpoints are the well-known CRI endpoints which are tried in order when no endpoint is specified
+var DefaultEndpoints = []string{
+	"unix:///run/containerd/containerd.sock",
+	"unix:///var/run/crio/crio.sock",
+	"unix:///var/run/cri-dockerd.sock",
+}
+
+// Client talks to any container runtime which implements the kubernetes CRI (runtime.v1) RuntimeService,
+// such as the CRI plugin of containerd, cri-o and cri-dockerd
+type Client struct {
+	conn          *grpc.ClientConn
+	runtimeClient runtimeapi.RuntimeServiceClient
+	imageClient   runtimeapi.ImageServiceClient
+	// key is the key of the client in the pool, the client is evicted by it if the connection is broken
+	key container.ClientKey
+
+	Ctx context.Context
+}
+
+// NewClient returns the client which talks to the CRI runtime service of the endpoint
+func NewClient(endpoint string) (*Client, error) {
+	if endpoint == "" {
+		endpoint = os.Getenv(RuntimeEndpointEnv)
+	}
+	if endpoint == "" {
+		endpoint = findDefaultEndpoint()
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("cannot find the cri endpoint from %s", strings.Join(DefaultEndpoints, ", "))
+	}
+	if strings.HasPrefix(endpoint, "/") {
+		endpoint = "unix://" + endpoint
+	}
+	key := container.ClientKey{Runtime: container.CriRuntime, Endpoint: endpoint}
+	cli, err := container.DefaultClientPool.Get(key, func() (container.PooledClient, error) {
+		cli, err := createClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		cli.key = key
+		return cli, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.(*Client), nil
+}
+
+// createClient connects to the endpoint and checks the runtime service is serving
+func createClient(endpoint string) (*Client, error) {
+	conn, err := grpc.NewClient(endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	cli := &Client{
+		conn:          conn,
+		runtimeClient: runtimeapi.NewRuntimeServiceClient(conn),
+		imageClient:   runtimeapi.NewImageServiceClient(conn),
+		Ctx:           context.Background(),
+	}
+	ctx, cancel := context.WithTimeout(cli.Ctx, connectionTimeout)
+	defer cancel()
+	if err := cli.Ping(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return cli, nil
+}
+
+// findDefaultEndpoint returns the first default endpoint whose socket file exists
+func findDefaultEndpoint() string {
+	for _, endpoint := range DefaultEndpoints {
+		if _, err := os.Stat(strings.TrimPrefix(endpoint, "unix://")); err == nil {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// Ping checks the runtime service is serving
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.runtimeClient.Version(ctx, &runtimeapi.VersionRequest{})
+	return err
+}
+
+// Close closes the connection to the runtime service
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) GetPidById(ctx context.Context, containerId string) (int32, error, int32) {
+	resp, err := c.runtimeClient.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
+		ContainerId: containerId,
+		Verbose:     true,
+	})
+	if err != nil {
+		return -1, errors.New(spec.ContainerExecFailed.Sprintf("ContainerStatus", err.Error())), spec.ContainerExecFailed.Code
+	}
+	pid, err := parsePidFromInfo(resp.Info)
+	if err != nil {
+		return -1, errors.New(spec.ContainerExecFailed.Sprintf("ContainerStatus", err.Error())), spec.ContainerExecFailed.Code
+	}
+	return pid, nil, spec.OK.Code
+}
+
+// parsePidFromInfo returns the pid from the verbose info of the container status
+func parsePidFromInfo(info map[string]string) (int32, error) {
+	raw, ok := info["info"]
+	if !ok {
+		return -1, errors.New("the verbose info of the container status is empty")
+	}
+	var verboseInfo struct {
+		Pid int32 `json:"pid"`
+	}
+	if err := json.Unmarshal([]byte(raw), &verboseInfo); err != nil {
+		return -1, err
+	}
+	if verboseInfo.Pid <= 0 {
+		return -1, errors.New("the container is not running")
+	}
+	return verboseInfo.Pid, nil
+}
+
+// GetContainerById returns the container whose id is prefixed by the containerId in any state, as crictl does
+func (c *Client) GetContainerById(ctx context.Context, containerId string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(ctx, container.ContainerFilter{ContainerId: containerId, IncludeSandbox: true})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerId, "container-id", containerId)
+}
+
+// GetContainerByName returns the container by the name in the container metadata
+func (c *Client) GetContainerByName(ctx context.Context, containerName string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(ctx, container.ContainerFilter{ContainerName: containerName})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerName, "container-name", containerName)
+}
+
+func (c *Client) GetContainerByLabelSelector(labels map[string]string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(c.Ctx, container.ContainerFilter{Labels: labels})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerId, "container-label-selector",
+		container.FormatLabels(labels))
+}
+
+func (c *Client) listContainers(ctx context.Context, filter *runtimeapi.ContainerFilter) ([]*runtimeapi.Container, error) {
+	resp, err := c.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: filter,
+	})
+	if err != nil {
+		container.EvictOnConnectionError(c.key, c, err)
+		return nil, err
+	}
+	return resp.Containers, nil
+}
+
+func containerNameOf(ctr *runtimeapi.Container) string {
+	if ctr.Metadata != nil && ctr.Metadata.Name != "" {
+		return ctr.Metadata.Name
+	}
+	return ctr.Labels[container.KubernetesContainerNameLabel]
+}
+
+func convertContainerInfo(ctr *runtimeapi.Container) container.ContainerInfo {
+	info := container.ContainerInfo{
+		ContainerId:   ctr.Id,
+		ContainerName: containerNameOf(ctr),
+		Labels:        ctr.Labels,
+		State:         convertState(ctr.State),
+		Image:         ctr.ImageRef,
+		Created:       time.Unix(0, ctr.CreatedAt),
+		Runtime:       container.CriRuntime,
+	}
+	if ctr.Image != nil && ctr.Image.Image != "" {
+		info.Image = ctr.Image.Image
+	}
+	return info
+}
+
+func convertState(state runtimeapi.ContainerState) string {
+	switch state {
+	case runtimeapi.ContainerState_CONTAINER_CREATED:
+		return container.StateCreated
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		return container.StateRunning
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		return container.StateExited
+	default:
+		return container.StateUnknown
+	}
+}
+
+// GetContainers returns all the containers matched the filter, the pid is set for the running containers
+func (c *Client) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	containers, err := c.listContainers(ctx, &runtimeapi.ContainerFilter{
+		LabelSelector: filter.Labels,
+	})
+	if err != nil {
+		return nil, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
+	}
+	result := make([]container.ContainerInfo, 0, len(containers))
+	for _, ctr := range containers {
+		info := convertContainerInfo(ctr)
+		if !filter.Match(info) {
+			continue
+		}
+		if info.State == container.StateRunning {
+			if pid, err, _ := c.GetPidById(ctx, info.ContainerId); err == nil {
+				info.Pid = pid
+			}
+		}
+		result = append(result, info)
+	}
+	return result, nil, spec.OK.Code
+}
+
+// RemoveContainer stops the container and then removes it
+func (c *Client) RemoveContainer(ctx context.Context, containerId string, force bool) error {
+	var timeout int64 = defaultStopTimeout
+	if force {
+		timeout = 0
+	}
+	if _, err := c.runtimeClient.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+		ContainerId: containerId,
+		Timeout:     timeout,
+	}); err != nil {
+		log.Warnf(ctx, "Stop container: %s, err: %s", containerId, err)
+		return err
+	}
+	if _, err := c.runtimeClient.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{
+		ContainerId: containerId,
+	}); err != nil {
+		log.Warnf(ctx, "Remove container: %s, err: %s", containerId, err)
+		return err
+	}
+	return nil
+}
+
+// ExecContainer executes the command in the container by the ExecSync api of the runtime service
+func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	log.Infof(ctx, "exec container cmd: %s, container: %s", command, containerId)
+	start := time.Now()
+	resp, err := c.runtimeClient.ExecSync(ctx, &runtimeapi.ExecSyncRequest{
+		ContainerId: containerId,
+		Cmd:         []string{"/bin/sh", "-c", command},
+	})
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	result := container.ExecResult{
+		ExitCode: int(resp.ExitCode),
+		Stdout:   string(resp.Stdout),
+		Stderr:   string(resp.Stderr),
+		Duration: time.Since(start),
+	}
+	log.Debugf(ctx, "Command Result, output: %s, errMsg: %s, exitCode: %d", result.Stdout, result.Stderr, result.ExitCode)
+	return result, nil
+}
+
+// ExecuteAndRemove creates a container in the pod sandbox of the target container, executes the command in it,
+// and removes the container
+func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Config, hostConfig *containertype.HostConfig,
+	networkConfig *network.NetworkingConfig, containerName string, removed bool, timeout time.Duration,
+	command string, containerInfo container.ContainerInfo,
+) (containerId string, result container.ExecResult, err error, code int32) {
+	// 1. get the pod sandbox of the target container
+	targets, err := c.listContainers(ctx, &runtimeapi.ContainerFilter{Id: containerInfo.ContainerId})
+	if err != nil || len(targets) == 0 {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("get target container failed, %v", err))), spec.CreateContainerFailed.Code
+	}
+	sandboxId := targets[0].PodSandboxId
+	sandboxConfig, err := c.getSandboxConfig(ctx, sandboxId)
+	if err != nil {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(fmt.Sprintf("get pod sandbox failed, %v", err))), spec.CreateContainerFailed.Code
+	}
+
+	// 2. pull image before create container
+	if err := c.pullImageIfNotPresent(ctx, config.Image, sandboxConfig); err != nil {
+		return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, err)), spec.ImagePullFailed.Code
+	}
+
+	// 3. create and start the container in the same sandbox
+	var capAdd []string
+	if hostConfig != nil {
+		capAdd = hostConfig.CapAdd
+	}
+	created, err := c.runtimeClient.CreateContainer(ctx, &runtimeapi.CreateContainerRequest{
+		PodSandboxId: sandboxId,
+		Config: &runtimeapi.ContainerConfig{
+			Metadata: &runtimeapi.ContainerMetadata{Name: containerName},
+			Image:    &runtimeapi.ImageSpec{Image: config.Image},
+			Command:  config.Cmd,
+			Labels:   config.Labels,
+			Stdin:    true,
+			Tty:      config.Tty,
+			Linux: &runtimeapi.LinuxContainerConfig{
+				SecurityContext: &runtimeapi.LinuxContainerSecurityContext{
+					Capabilities:     &runtimeapi.Capability{AddCapabilities: capAdd},
+					NamespaceOptions: sandboxConfig.GetLinux().GetSecurityContext().GetNamespaceOptions(),
+				},
+			},
+		},
+		SandboxConfig: sandboxConfig,
+	})
+	if err != nil {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(err)), spec.CreateContainerFailed.Code
+	}
+	containerId = created.ContainerId
+	if _, err := c.runtimeClient.StartContainer(ctx, &runtimeapi.StartContainerRequest{ContainerId: containerId}); err != nil {
+		c.RemoveContainer(ctx, containerId, true)
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("CreateAndStartContainer", err)), spec.ContainerExecFailed.Code
+	}
+
+	// 4. exec command in the new container
+	result, err = c.ExecContainer(ctx, containerId, command)
+	if removed {
+		c.RemoveContainer(ctx, containerId, true)
+	}
+	if err != nil {
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("ContainerExecCmd", err)), spec.ContainerExecFailed.Code
+	}
+	log.Infof(ctx, "Execute output in container: %s, exit code: %d", result.Output(), result.ExitCode)
+	return containerId, result, nil, spec.OK.Code
+}
+
+// getSandboxConfig rebuilds the pod sandbox config which is required by the container creation
+func (c *Client) getSandboxConfig(ctx context.Context, sandboxId string) (*runtimeapi.PodSandboxConfig, error) {
+	resp, err := c.runtimeClient.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: sandboxId})
+	if err != nil {
+		return nil, err
+	}
+	status := resp.Status
+	if status == nil {
+		return nil, fmt.Errorf("the status of the pod sandbox %s is empty", sandboxId)
+	}
+	return &runtimeapi.PodSandboxConfig{
+		Metadata:    status.Metadata,
+		Labels:      status.Labels,
+		Annotations: status.Annotations,
+		Linux: &runtimeapi.LinuxPodSandboxConfig{
+			SecurityContext: &runtimeapi.LinuxSandboxSecurityContext{
+				NamespaceOptions: status.GetLinux().GetNamespaces().GetOptions(),
+			},
+		},
+	}, nil
+}
+
+func (c *Client) pullImageIfNotPresent(ctx context.Context, ref string, sandboxConfig *runtimeapi.PodSandboxConfig) error {
+	imageSpec := &runtimeapi.ImageSpec{Image: ref}
+	status, err := c.imageClient.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{Image: imageSpec})
+	if err == nil && status.Image != nil {
+		return nil
+	}
+	_, err = c.imageClient.PullImage(ctx, &runtimeapi.PullImageRequest{
+		Image:         imageSpec,
+		SandboxConfig: sandboxConfig,
+	})
+	return err
+}
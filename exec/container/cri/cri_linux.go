@@ -0,0 +1,35 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cri
+
+import (
+	"context"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// CopyToContainer copies a tar file to the dstPath.
+// The CRI runtime service has no copy api, so the file is copied by entering the mount namespace of the container
+func (c *Client) CopyToContainer(ctx context.Context, containerId, srcFile, dstPath, extractDirName string, override bool) error {
+	pid, err, _ := c.GetPidById(ctx, containerId)
+	if err != nil {
+		return err
+	}
+	return container.CopyToContainer(ctx, uint32(pid), srcFile, dstPath, extractDirName, override)
+}
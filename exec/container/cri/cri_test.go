@@ -0,0 +1,245 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cri
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeRuntimeService serves the containers and the exec results of the CRI runtime service
+type fakeRuntimeService struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+
+	containers []*runtimeapi.Container
+	pids       map[string]int32
+	exec       func(req *runtimeapi.ExecSyncRequest) *runtimeapi.ExecSyncResponse
+}
+
+func (s *fakeRuntimeService) Version(ctx context.Context, req *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{RuntimeName: "fake", RuntimeApiVersion: "v1"}, nil
+}
+
+func (s *fakeRuntimeService) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	result := make([]*runtimeapi.Container, 0, len(s.containers))
+	for _, ctr := range s.containers {
+		if req.Filter != nil && !matchLabels(req.Filter.LabelSelector, ctr.Labels) {
+			continue
+		}
+		result = append(result, ctr)
+	}
+	return &runtimeapi.ListContainersResponse{Containers: result}, nil
+}
+
+func (s *fakeRuntimeService) ContainerStatus(ctx context.Context, req *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	pid, ok := s.pids[req.ContainerId]
+	if !ok {
+		return nil, fmt.Errorf("container %s not found", req.ContainerId)
+	}
+	return &runtimeapi.ContainerStatusResponse{
+		Status: &runtimeapi.ContainerStatus{Id: req.ContainerId},
+		Info:   map[string]string{"info": fmt.Sprintf(`{"pid":%d}`, pid)},
+	}, nil
+}
+
+func (s *fakeRuntimeService) ExecSync(ctx context.Context, req *runtimeapi.ExecSyncRequest) (*runtimeapi.ExecSyncResponse, error) {
+	return s.exec(req), nil
+}
+
+func matchLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// startFakeRuntimeService serves the service on a unix socket and returns the client connected to it
+func startFakeRuntimeService(t *testing.T, service *fakeRuntimeService) *Client {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen %s: %v", socket, err)
+	}
+	server := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cli, err := NewClient(socket)
+	if err != nil {
+		t.Fatalf("NewClient(%s): %v", socket, err)
+	}
+	t.Cleanup(func() { container.DefaultClientPool.CloseAll() })
+	return cli
+}
+
+func newContainer(id, name string, state runtimeapi.ContainerState, labels map[string]string) *runtimeapi.Container {
+	return &runtimeapi.Container{
+		Id:       id,
+		Metadata: &runtimeapi.ContainerMetadata{Name: name},
+		Image:    &runtimeapi.ImageSpec{Image: "docker.io/library/nginx:latest"},
+		State:    state,
+		Labels:   labels,
+	}
+}
+
+func testContainers() *fakeRuntimeService {
+	return &fakeRuntimeService{
+		containers: []*runtimeapi.Container{
+			newContainer("abc123", "nginx", runtimeapi.ContainerState_CONTAINER_RUNNING,
+				map[string]string{"app": "nginx", container.KubernetesContainerNameLabel: "nginx"}),
+			newContainer("abd456", "sidecar", runtimeapi.ContainerState_CONTAINER_EXITED,
+				map[string]string{"app": "nginx", container.KubernetesContainerNameLabel: "sidecar"}),
+			newContainer("fff789", "POD", runtimeapi.ContainerState_CONTAINER_RUNNING,
+				map[string]string{"app": "nginx", container.KubernetesContainerNameLabel: container.KubernetesPodSandboxName}),
+		},
+		pids: map[string]int32{"abc123": 1234, "fff789": 1},
+	}
+}
+
+func TestClientPing(t *testing.T) {
+	cli := startFakeRuntimeService(t, testContainers())
+	if err := cli.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+}
+
+func TestClientGetContainers(t *testing.T) {
+	cli := startFakeRuntimeService(t, testContainers())
+	tests := []struct {
+		name   string
+		filter container.ContainerFilter
+		want   []string
+	}{
+		{"all except sandbox", container.ContainerFilter{}, []string{"abc123", "abd456"}},
+		{"include sandbox", container.ContainerFilter{IncludeSandbox: true}, []string{"abc123", "abd456", "fff789"}},
+		{"by labels", container.ContainerFilter{Labels: map[string]string{container.KubernetesContainerNameLabel: "sidecar"}}, []string{"abd456"}},
+		{"by name", container.ContainerFilter{ContainerName: "nginx"}, []string{"abc123"}},
+		{"by id prefix", container.ContainerFilter{ContainerId: "ab"}, []string{"abc123", "abd456"}},
+		{"no match", container.ContainerFilter{ContainerName: "redis"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err, _ := cli.GetContainers(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetContainers() err = %v", err)
+			}
+			var ids []string
+			for _, ctr := range containers {
+				ids = append(ids, ctr.ContainerId)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("GetContainers() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetContainersInfo(t *testing.T) {
+	cli := startFakeRuntimeService(t, testContainers())
+	containers, err, _ := cli.GetContainers(context.Background(), container.ContainerFilter{})
+	if err != nil {
+		t.Fatalf("GetContainers() err = %v", err)
+	}
+	want := map[string]container.ContainerInfo{
+		"abc123": {ContainerName: "nginx", State: container.StateRunning, Pid: 1234},
+		"abd456": {ContainerName: "sidecar", State: container.StateExited},
+	}
+	for _, ctr := range containers {
+		expected := want[ctr.ContainerId]
+		if ctr.ContainerName != expected.ContainerName || ctr.State != expected.State || ctr.Pid != expected.Pid {
+			t.Errorf("container %s = {%s %s %d}, want {%s %s %d}", ctr.ContainerId, ctr.ContainerName, ctr.State,
+				ctr.Pid, expected.ContainerName, expected.State, expected.Pid)
+		}
+		if ctr.Runtime != container.CriRuntime || ctr.Image != "docker.io/library/nginx:latest" {
+			t.Errorf("container %s runtime = %s, image = %s", ctr.ContainerId, ctr.Runtime, ctr.Image)
+		}
+	}
+}
+
+func TestClientGetContainerById(t *testing.T) {
+	cli := startFakeRuntimeService(t, testContainers())
+	tests := []struct {
+		name        string
+		containerId string
+		want        string
+		code        int32
+	}{
+		{"full id", "abc123", "abc123", spec.OK.Code},
+		{"unique prefix", "abd", "abd456", spec.OK.Code},
+		{"sandbox", "fff", "fff789", spec.OK.Code},
+		{"ambiguous prefix", "ab", "", spec.ParameterInvalid.Code},
+		{"not found", "xyz", "", spec.ParameterInvalidDockContainerId.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err, code := cli.GetContainerById(context.Background(), tt.containerId)
+			if code != tt.code {
+				t.Fatalf("GetContainerById(%s) code = %d, err = %v, want code %d", tt.containerId, code, err, tt.code)
+			}
+			if info.ContainerId != tt.want {
+				t.Errorf("GetContainerById(%s) = %s, want %s", tt.containerId, info.ContainerId, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetContainerByName(t *testing.T) {
+	cli := startFakeRuntimeService(t, testContainers())
+	info, err, _ := cli.GetContainerByName(context.Background(), "sidecar")
+	if err != nil || info.ContainerId != "abd456" {
+		t.Errorf("GetContainerByName(sidecar) = %s, %v, want abd456", info.ContainerId, err)
+	}
+	_, err, code := cli.GetContainerByName(context.Background(), "redis")
+	if err == nil || code != spec.ParameterInvalidDockContainerName.Code {
+		t.Errorf("GetContainerByName(redis) code = %d, err = %v, want code %d", code, err,
+			spec.ParameterInvalidDockContainerName.Code)
+	}
+}
+
+func TestClientExecContainer(t *testing.T) {
+	service := testContainers()
+	var cmd []string
+	service.exec = func(req *runtimeapi.ExecSyncRequest) *runtimeapi.ExecSyncResponse {
+		cmd = req.Cmd
+		return &runtimeapi.ExecSyncResponse{Stdout: []byte("out"), Stderr: []byte("err"), ExitCode: 2}
+	}
+	cli := startFakeRuntimeService(t, service)
+	result, err := cli.ExecContainer(context.Background(), "abc123", "echo out")
+	if err != nil {
+		t.Fatalf("ExecContainer() err = %v", err)
+	}
+	if strings.Join(cmd, " ") != "/bin/sh -c echo out" {
+		t.Errorf("ExecContainer() cmd = %q", cmd)
+	}
+	if result.Stdout != "out" || result.Stderr != "err" || result.ExitCode != 2 {
+		t.Errorf("ExecContainer() = %+v, want stdout out, stderr err, exit code 2", result)
+	}
+}
@@ -36,44 +36,69 @@ import (
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
-var cli *Client
-
 type Client struct {
 	client *client.Client
 	Ctx    context.Context
+
+	// key is the key of the client in the pool, the client is evicted by it if the connection is broken
+	key container.ClientKey
 }
 
 // GetClient returns the docker client
 func NewClient(endpoint string) (*Client, error) {
-	var oldClient *client.Client
-	if cli != nil {
-		oldClient = cli.client
+	key := container.ClientKey{Runtime: container.DockerRuntime, Endpoint: endpoint}
+	cli, err := container.DefaultClientPool.Get(key, func() (container.PooledClient, error) {
+		client, err := createClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return &Client{
+			client: client,
+			Ctx:    context.TODO(),
+			key:    key,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.(*Client), nil
+}
+
+// createClient
+func createClient(endpoint string) (*client.Client, error) {
+	var cli *client.Client
+	var err error
+	if endpoint == "" {
+		cli, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.24"))
+	} else {
+		cli, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.24"), client.WithHost(endpoint))
 	}
-	client, err := checkAndCreateClient(endpoint, oldClient)
 	if err != nil {
 		return nil, err
 	}
-	cli = &Client{
-		client: client,
-		Ctx:    context.TODO(),
+	if _, err := ping(cli); err != nil {
+		cli.Close()
+		return nil, err
 	}
 	return cli, nil
 }
 
-// checkAndCreateClient
-func checkAndCreateClient(endpoint string, cli *client.Client) (*client.Client, error) {
-	if cli == nil {
-		var err error
-		if endpoint == "" {
-			cli, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.24"))
-		} else {
-			cli, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.24"), client.WithHost(endpoint))
-		}
-		if err != nil {
-			return nil, err
-		}
+// Ping checks the docker daemon is serving
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.client.Ping(ctx)
+	return err
+}
+
+// Close closes the connection to the docker daemon
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// evictIfBroken evicts the client from the pool if the connection to the docker daemon is broken
+func (c *Client) evictIfBroken(err error) {
+	if client.IsErrConnectionFailed(err) || container.IsConnectionError(err) {
+		container.DefaultClientPool.Evict(c.key, c)
 	}
-	return ping(cli)
 }
 
 // ping
@@ -107,7 +132,14 @@ func (c *Client) GetPidById(ctx context.Context, containerId string) (int32, err
 	if err != nil {
 		return -1, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
 	}
-
+	if inspect.State == nil || inspect.State.Pid <= 0 {
+		status := container.StateUnknown
+		if inspect.State != nil {
+			status = inspect.State.Status
+		}
+		err := fmt.Errorf("the container %s is %s, it has no running process", containerId, status)
+		return -1, errors.New(spec.ContainerExecFailed.Sprintf("GetPidById", err.Error())), spec.ContainerExecFailed.Code
+	}
 	return int32(inspect.State.Pid), nil, spec.OK.Code
 }
 
@@ -147,6 +179,7 @@ func (c *Client) GetContainerByLabelSelector(labels map[string]string) (containe
 func (c *Client) GetContainerFromDocker(option containertype.ListOptions) (container.ContainerInfo, error, int32) {
 	containers, err := c.client.ContainerList(context.Background(), option)
 	if err != nil {
+		c.evictIfBroken(err)
 		return container.ContainerInfo{}, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
 	}
 	if containers == nil || len(containers) == 0 {
@@ -157,11 +190,64 @@ func (c *Client) GetContainerFromDocker(option containertype.ListOptions) (conta
 }
 
 func convertContainerInfo(container2 types.Container) container.ContainerInfo {
+	var containerName string
+	if len(container2.Names) > 0 {
+		containerName = container2.Names[0]
+	}
 	return container.ContainerInfo{
 		ContainerId:   container2.ID,
-		ContainerName: container2.Names[0],
+		ContainerName: containerName,
 		Labels:        container2.Labels,
+		State:         container.ParseState(container2.State),
+		Image:         container2.Image,
+		Created:       time.Unix(container2.Created, 0),
+		Runtime:       container.DockerRuntime,
+	}
+}
+
+// GetContainers returns all the containers matched the filter, the stopped containers are contained
+func (c *Client) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	args := filters.NewArgs()
+	if filter.ContainerId != "" {
+		args.Add("id", filter.ContainerId)
+	}
+	if filter.ContainerName != "" {
+		args.Add("name", filter.ContainerName)
+	}
+	for k, v := range filter.Labels {
+		args.Add("label", fmt.Sprintf("%s=%s", k, v))
+	}
+	if filter.Image != "" && !container.IsImageRegexp(filter.Image) {
+		args.Add("ancestor", filter.Image)
+	}
+	containers, err := c.client.ContainerList(context.Background(), containertype.ListOptions{
+		All:     true,
+		Filters: args,
+	})
+	if err != nil {
+		c.evictIfBroken(err)
+		if client.IsErrNotFound(err) && args.Contains("ancestor") {
+			// the image of the ancestor filter does not exist, so no container is running it
+			return []container.ContainerInfo{}, nil, spec.OK.Code
+		}
+		return nil, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
+	}
+	result := make([]container.ContainerInfo, 0, len(containers))
+	for _, ctr := range containers {
+		info := convertContainerInfo(ctr)
+		// the name filter of docker matches a part of the name, and the ancestor filter matches the images built
+		// from the image, so match them again
+		if !filter.Match(info) {
+			continue
+		}
+		if info.State == container.StateRunning || info.State == container.StatePaused {
+			if pid, err, _ := c.GetPidById(ctx, info.ContainerId); err == nil {
+				info.Pid = pid
+			}
+		}
+		result = append(result, info)
 	}
+	return result, nil, spec.OK.Code
 }
 
 // RemoveContainer
@@ -181,7 +267,7 @@ func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Con
 	networkConfig *network.NetworkingConfig, containerName string, removed bool,
 	timeout time.Duration,
 	command string, containerInfo container.ContainerInfo,
-) (containerId string, output string, err error, code int32) {
+) (containerId string, result container.ExecResult, err error, code int32) {
 	log.Debugf(ctx, "command: '%s', image: %s, containerName: %s", command, config.Image, containerName)
 	// check image exists or not
 	_, err = c.getImageByRef(ctx, config.Image)
@@ -189,27 +275,27 @@ func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Con
 		// pull image if not exists
 		_, err := c.pullImage(config.Image)
 		if err != nil {
-			return "", "", errors.New(spec.ImagePullFailed.Sprintf(config.Image, err)), spec.ImagePullFailed.Code
+			return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, err)), spec.ImagePullFailed.Code
 		}
 	}
 	containerId, err = c.createAndStartContainer(ctx, config, hostConfig, networkConfig, containerName)
 	if err != nil {
 		c.RemoveContainer(ctx, containerId, true)
-		return containerId, "", errors.New(spec.ContainerExecFailed.Sprintf("CreateAndStartContainer", err)), spec.ContainerExecFailed.Code
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("CreateAndStartContainer", err)), spec.ContainerExecFailed.Code
 	}
 
-	output, err = c.ExecContainer(ctx, containerId, command)
+	result, err = c.ExecContainer(ctx, containerId, command)
 	if err != nil {
 		if removed {
 			c.RemoveContainer(ctx, containerId, true)
 		}
-		return containerId, "", errors.New(spec.ContainerExecFailed.Sprintf("ContainerExecCmd", err)), spec.ContainerExecFailed.Code
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("ContainerExecCmd", err)), spec.ContainerExecFailed.Code
 	}
-	log.Infof(ctx, "Execute output in container: %s", output)
+	log.Infof(ctx, "Execute output in container: %s, exit code: %d", result.Output(), result.ExitCode)
 	if removed {
 		c.RemoveContainer(ctx, containerId, true)
 	}
-	return containerId, output, nil, spec.OK.Code
+	return containerId, result, nil, spec.OK.Code
 }
 
 // ImageExists
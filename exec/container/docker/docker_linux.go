@@ -35,14 +35,48 @@ package docker
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
 
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
-func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (output string, err error) {
+// CheckNSExec returns an error if the docker daemon is remote, the container runs in a sandbox runtime or
+// its process is not found on the local host, the result is cached in the context of the experiment
+func (c *Client) CheckNSExec(ctx context.Context, containerId string) error {
+	return container.CachedNSExecCheck(ctx, containerId, func() error {
+		return c.checkNSExec(ctx, containerId)
+	})
+}
+
+func (c *Client) checkNSExec(ctx context.Context, containerId string) error {
+	if host := c.client.DaemonHost(); container.IsRemoteEndpoint(host) {
+		return fmt.Errorf("the docker daemon %s is remote", host)
+	}
+	inspect, err := c.client.ContainerInspect(ctx, containerId)
+	if err != nil {
+		return err
+	}
+	if inspect.ContainerJSONBase == nil || inspect.State == nil {
+		return fmt.Errorf("the state of the container %s is unknown", containerId)
+	}
+	if inspect.HostConfig != nil && container.IsSandboxRuntime(inspect.HostConfig.Runtime) {
+		return fmt.Errorf("the container %s runs in the sandbox runtime %s", containerId, inspect.HostConfig.Runtime)
+	}
+	return container.CheckHostProcess(int32(inspect.State.Pid))
+}
+
+// ExecContainer executes the command in the namespaces of the container process by nsexec,
+// the exec api is used if nsexec is not viable for the container
+func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	if err := c.CheckNSExec(ctx, containerId); err != nil {
+		log.Infof(ctx, "execute the command in the container %s by the exec api, %v", containerId, err)
+		return c.execContainerByAPI(ctx, containerId, command)
+	}
 	id, err, _ := c.GetPidById(ctx, containerId)
 	if err != nil {
-		return "", err
+		return container.ExecResult{}, err
 	}
 	return container.ExecContainer(ctx, id, command)
 }
@@ -50,6 +84,10 @@ func (c *Client) ExecContainer(ctx context.Context, containerId, command string)
 // CopyToContainer copies a tar file to the dstPath.
 // If the same file exits in the dstPath, it will be override if the override arg is true, otherwise not
 func (c *Client) CopyToContainer(ctx context.Context, containerId, srcFile, dstPath, extractDirName string, override bool) error {
+	if err := c.CheckNSExec(ctx, containerId); err != nil {
+		log.Infof(ctx, "copy the file to the container %s by the archive api, %v", containerId, err)
+		return c.copyToContainerByAPI(ctx, containerId, srcFile, dstPath, override)
+	}
 	id, err, _ := c.GetPidById(ctx, containerId)
 	if err != nil {
 		return err
@@ -37,9 +37,11 @@ package docker
 import (
 	"context"
 	"fmt"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
 // ExecContainer executes a command in a running container
-func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (output string, err error) {
-	return "", fmt.Errorf("ExecContainer not implemented for Windows platform")
+func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	return container.ExecResult{}, fmt.Errorf("ExecContainer not implemented for Windows platform")
 }
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/docker/docker/api/types/container"
+
+	execContainer "github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// execContainer with command which does not contain "sh -c" in the target container
+func execContainerWithConf(ctx context.Context, containerId, command string, config container.ExecOptions, c *Client) (execContainer.ExecResult, error) {
+	log.Infof(ctx, "execute command: %s", strings.Join(config.Cmd, " "))
+	start := time.Now()
+	id, err := c.client.ContainerExecCreate(ctx, containerId, config)
+	if err != nil {
+		log.Warnf(ctx, "Create exec for container: %s, err: %s", containerId, err.Error())
+		return execContainer.ExecResult{}, err
+	}
+	resp, err := c.client.ContainerExecAttach(ctx, id.ID, container.ExecAttachOptions{})
+	if err != nil {
+		log.Warnf(ctx, "Attach exec for container: %s, err: %s", containerId, err.Error())
+		return execContainer.ExecResult{}, err
+	}
+	defer resp.Close()
+	result, err := execContainer.ReadExecResult(resp.Reader, start, func() (int, error) {
+		inspect, err := c.client.ContainerExecInspect(ctx, id.ID)
+		return inspect.ExitCode, err
+	})
+	if err != nil {
+		log.Warnf(ctx, "Exec in container: %s, err: %s", containerId, err.Error())
+		return result, err
+	}
+	log.Debugf(ctx, "execute result: %s, error msg: %s, exit code: %d", result.Stdout, result.Stderr, result.ExitCode)
+	return result, nil
+}
+
+// execContainerByAPI executes the command by /bin/sh -c in the container by the exec api of the docker daemon,
+// which works for the remote daemon and the sandboxed containers
+func (c *Client) execContainerByAPI(ctx context.Context, containerId, command string) (execContainer.ExecResult, error) {
+	return execContainerWithConf(ctx, containerId, command, container.ExecOptions{
+		AttachStderr: true,
+		AttachStdout: true,
+		Cmd:          []string{"sh", "-c", command},
+		Privileged:   true,
+		User:         "root",
+	}, c)
+}
+
+// copyToContainerByAPI copies a tar file to the dstPath by the archive api of the docker daemon, the compressed
+// tar is extracted by the daemon
+func (c *Client) copyToContainerByAPI(ctx context.Context, containerId, srcFile, dstPath string, override bool) error {
+	// must be a tar file
+	options := container.CopyToContainerOptions{
+		AllowOverwriteDirWithFile: override,
+		CopyUIDGID:                true,
+	}
+	result, err := c.execContainerByAPI(ctx, containerId, execContainer.ShellJoin("mkdir", "-p", dstPath))
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(srcFile, os.O_RDONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return c.client.CopyToContainer(c.Ctx, containerId, dstPath, file, options)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// IsImageRegexp returns true if the image is a regular expression with the regex: prefix rather than an image reference
+func IsImageRegexp(image string) bool {
+	return strings.HasPrefix(image, RegexpPatternPrefix)
+}
+
+// ValidateImagePattern checks the regular expression of the image with the regex: prefix
+func ValidateImagePattern(image string) error {
+	if expr, ok := strings.CutPrefix(image, RegexpPatternPrefix); ok {
+		_, err := regexp.Compile("^(?:" + expr + ")$")
+		return err
+	}
+	return nil
+}
+
+// NormalizeImage returns the fully qualified reference of the image, such as docker.io/library/redis:latest for redis.
+// The image is returned as it is if it is not a valid reference
+func NormalizeImage(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.TagNameOnly(named).String()
+}
+
+// MatchImage returns true if the image matches the pattern, which is an image reference or a regular expression with
+// the regex: prefix. The regular expression must match the whole image reference, either as it is or fully qualified
+func MatchImage(pattern, image string) bool {
+	if pattern == "" {
+		return true
+	}
+	if image == "" {
+		return false
+	}
+	expr, ok := strings.CutPrefix(pattern, RegexpPatternPrefix)
+	if !ok {
+		return image == pattern || NormalizeImage(image) == NormalizeImage(pattern)
+	}
+	imageRegexp, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return false
+	}
+	return imageRegexp.MatchString(image) || imageRegexp.MatchString(NormalizeImage(image))
+}
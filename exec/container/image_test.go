@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"testing"
+)
+
+func TestMatchImage(t *testing.T) {
+	tests := []struct {
+		pattern string
+		image   string
+		want    bool
+	}{
+		{"", "nginx", true},
+		{"nginx", "", false},
+		{"nginx", "nginx", true},
+		{"nginx", "docker.io/library/nginx:latest", true},
+		{"docker.io/library/nginx", "nginx:latest", true},
+		{"nginx:1.25", "nginx:latest", false},
+		{"redis", "nginx", false},
+		{"regex:nginx:.*", "nginx:1.25", true},
+		{"regex:nginx", "nginx:1.25", false},
+		{"regex:docker.io/library/nginx:.*", "nginx", true},
+		{"regex:.*/pause:.*", "registry.k8s.io/pause:3.9", true},
+		{"regex:(", "nginx", false},
+		{"nginx.*", "nginx:latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.image, func(t *testing.T) {
+			if got := MatchImage(tt.pattern, tt.image); got != tt.want {
+				t.Errorf("MatchImage(%q, %q) = %v, want %v", tt.pattern, tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateImagePattern(t *testing.T) {
+	tests := []struct {
+		image   string
+		wantErr bool
+	}{
+		{"nginx:latest", false},
+		{"nginx.*", false},
+		{"regex:nginx:.*", false},
+		{"regex:(", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if err := ValidateImagePattern(tt.image); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateImagePattern(%q) err = %v, wantErr %v", tt.image, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The operators of the label selector requirement
+const (
+	OperatorEquals       = "="
+	OperatorNotEquals    = "!="
+	OperatorIn           = "in"
+	OperatorNotIn        = "notin"
+	OperatorExists       = "exists"
+	OperatorDoesNotExist = "!"
+)
+
+var (
+	labelKeyRegexp    = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+	setRequirementExp = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// Requirement is one expression of the label selector, such as `app in (web,api)`
+type Requirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// Matches returns true if the labels satisfy the requirement
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+	switch r.Operator {
+	case OperatorEquals:
+		return exists && value == r.Values[0]
+	case OperatorNotEquals:
+		return !exists || value != r.Values[0]
+	case OperatorIn:
+		return exists && containsString(r.Values, value)
+	case OperatorNotIn:
+		return !exists || !containsString(r.Values, value)
+	case OperatorExists:
+		return exists
+	case OperatorDoesNotExist:
+		return !exists
+	default:
+		return false
+	}
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case OperatorEquals, OperatorNotEquals:
+		return fmt.Sprintf("%s%s%s", r.Key, r.Operator, r.Values[0])
+	case OperatorIn, OperatorNotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	case OperatorDoesNotExist:
+		return "!" + r.Key
+	default:
+		return r.Key
+	}
+}
+
+// LabelSelector is the kubernetes style label selector, all the requirements must be satisfied
+type LabelSelector []Requirement
+
+// ParseLabelSelector parses the selector, such as `app=web,tier notin (db),canary,!legacy`.
+// The value of `=`, `==` and `!=` is the rest of the expression, so it can contain `=`
+func ParseLabelSelector(raw string) (LabelSelector, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	expressions, err := splitExpressions(raw)
+	if err != nil {
+		return nil, err
+	}
+	selector := make(LabelSelector, 0, len(expressions))
+	for _, expression := range expressions {
+		requirement, err := parseRequirement(strings.TrimSpace(expression))
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// splitExpressions splits the selector by the commas which are not in the parentheses
+func splitExpressions(raw string) ([]string, error) {
+	expressions := make([]string, 0)
+	depth, start := 0, 0
+	for idx, ch := range raw {
+		switch ch {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("nested parentheses in `%s`", raw)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in `%s`", raw)
+			}
+		case ',':
+			if depth == 0 {
+				expressions = append(expressions, raw[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in `%s`", raw)
+	}
+	return append(expressions, raw[start:]), nil
+}
+
+func parseRequirement(expression string) (Requirement, error) {
+	if expression == "" {
+		return Requirement{}, fmt.Errorf("empty expression")
+	}
+	if strings.HasPrefix(expression, "!") && !strings.Contains(expression, "=") {
+		return newRequirement(strings.TrimSpace(expression[1:]), OperatorDoesNotExist, nil)
+	}
+	if matches := setRequirementExp.FindStringSubmatch(expression); matches != nil {
+		values := make([]string, 0)
+		for _, value := range strings.Split(matches[3], ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return Requirement{}, fmt.Errorf("empty value in `%s`", expression)
+			}
+			values = append(values, value)
+		}
+		return newRequirement(matches[1], matches[2], values)
+	}
+	if idx := strings.Index(expression, "="); idx >= 0 {
+		if idx > 0 && expression[idx-1] == '!' {
+			return newRequirement(strings.TrimSpace(expression[:idx-1]), OperatorNotEquals,
+				[]string{strings.TrimSpace(expression[idx+1:])})
+		}
+		value := expression[idx+1:]
+		// `==` is the same as `=`
+		value = strings.TrimPrefix(value, "=")
+		return newRequirement(strings.TrimSpace(expression[:idx]), OperatorEquals, []string{strings.TrimSpace(value)})
+	}
+	return newRequirement(expression, OperatorExists, nil)
+}
+
+func newRequirement(key, operator string, values []string) (Requirement, error) {
+	if !labelKeyRegexp.MatchString(key) {
+		return Requirement{}, fmt.Errorf("invalid label key `%s`", key)
+	}
+	return Requirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+// Matches returns true if the labels satisfy all the requirements
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualityLabels returns the labels of the equality requirements, which can be translated into the native
+// filters of the runtimes
+func (s LabelSelector) EqualityLabels() map[string]string {
+	labels := make(map[string]string)
+	for _, requirement := range s {
+		if requirement.Operator == OperatorEquals {
+			labels[requirement.Key] = requirement.Values[0]
+		}
+	}
+	return labels
+}
+
+func (s LabelSelector) String() string {
+	expressions := make([]string, 0, len(s))
+	for _, requirement := range s {
+		expressions = append(expressions, requirement.String())
+	}
+	sort.Strings(expressions)
+	return strings.Join(expressions, ",")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "", want: ""},
+		{raw: "app=web", want: "app=web"},
+		{raw: "app==web", want: "app=web"},
+		{raw: "app!=web", want: "app!=web"},
+		{raw: " app = web , tier != db ", want: "app=web,tier!=db"},
+		{raw: "config=a=b", want: "config=a=b"},
+		{raw: "app in (web, api)", want: "app in (web,api)"},
+		{raw: "tier notin (db),app=web", want: "app=web,tier notin (db)"},
+		{raw: "canary,!legacy", want: "!legacy,canary"},
+		{raw: "app.kubernetes.io/name=web", want: "app.kubernetes.io/name=web"},
+		{raw: "app in (web,)", wantErr: true},
+		{raw: "app in ((web))", wantErr: true},
+		{raw: "app in (web", wantErr: true},
+		{raw: "app in web)", wantErr: true},
+		{raw: "app=web,,tier=db", wantErr: true},
+		{raw: "-app=web", wantErr: true},
+		{raw: "!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLabelSelector(%q) err = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if err == nil && selector.String() != tt.want {
+				t.Errorf("ParseLabelSelector(%q) = %q, want %q", tt.raw, selector.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend", "canary": ""}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"app=web", true},
+		{"app=api", false},
+		{"app!=api", true},
+		{"missing!=api", true},
+		{"app in (web,api)", true},
+		{"app in (api)", false},
+		{"missing in (web)", false},
+		{"tier notin (db)", true},
+		{"tier notin (frontend)", false},
+		{"missing notin (db)", true},
+		{"canary", true},
+		{"missing", false},
+		{"!legacy", true},
+		{"!canary", false},
+		{"app=web,tier notin (db),canary,!legacy", true},
+		{"app=web,tier=db", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseLabelSelector(%q) err = %v", tt.selector, err)
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("%q.Matches(%v) = %v, want %v", tt.selector, labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorEqualityLabels(t *testing.T) {
+	selector, err := ParseLabelSelector("app=web,tier!=db,env in (prod),canary,version==v1")
+	if err != nil {
+		t.Fatalf("ParseLabelSelector() err = %v", err)
+	}
+	want := map[string]string{"app": "web", "version": "v1"}
+	if got := selector.EqualityLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EqualityLabels() = %v, want %v", got, want)
+	}
+}
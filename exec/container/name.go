@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RegexpPatternPrefix is the prefix of the name pattern which is a regular expression, the others are glob patterns
+const RegexpPatternPrefix = "regex:"
+
+// ValidateNamePattern checks the glob pattern or the regular expression with the regex: prefix
+func ValidateNamePattern(pattern string) error {
+	if expr, ok := strings.CutPrefix(pattern, RegexpPatternPrefix); ok {
+		if _, err := regexp.Compile(expr); err != nil {
+			return err
+		}
+		return nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern `%s`, %v", pattern, err)
+	}
+	return nil
+}
+
+// MatchName returns true if the container name matches the pattern. The pattern is a glob pattern, such as payment-*,
+// or a regular expression with the regex: prefix, such as regex:^payment-v[0-9]+$. The leading slash of the name
+// is ignored
+func MatchName(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	name = strings.TrimPrefix(name, "/")
+	if expr, ok := strings.CutPrefix(pattern, RegexpPatternPrefix); ok {
+		nameRegexp, err := regexp.Compile(expr)
+		if err != nil {
+			return false
+		}
+		return nameRegexp.MatchString(name)
+	}
+	matched, err := path.Match(strings.TrimPrefix(pattern, "/"), name)
+	return err == nil && matched
+}
+
+// MatchAnyName returns true if the container name matches any of the patterns
+func MatchAnyName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchName(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNamePatterns splits the names and the name patterns separated by commas. A regular expression may contain
+// commas, so the item with the regex: prefix extends to the end of the value, it must be the last one and combines
+// several expressions by |
+func ParseNamePatterns(value string) []string {
+	patterns := make([]string, 0)
+	for value != "" {
+		var item string
+		if strings.HasPrefix(strings.TrimSpace(value), RegexpPatternPrefix) {
+			item, value = value, ""
+		} else {
+			item, value, _ = strings.Cut(value, ",")
+		}
+		if item = strings.TrimSpace(item); item != "" {
+			patterns = append(patterns, item)
+		}
+	}
+	return patterns
+}
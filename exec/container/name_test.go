@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"", "nginx", true},
+		{"nginx", "nginx", true},
+		{"nginx", "/nginx", true},
+		{"/nginx", "nginx", true},
+		{"payment-*", "payment-v1", true},
+		{"payment-*", "order-v1", false},
+		{"payment-?", "payment-12", false},
+		{"regex:^payment-v[0-9]+$", "payment-v12", true},
+		{"regex:^payment-v[0-9]+$", "payment-vx", false},
+		{"regex:pay", "/payment", true},
+		{"regex:(", "payment", false},
+		{"[", "payment", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := MatchName(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchName(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNamePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{"payment-*", false},
+		{"regex:^payment-v[0-9]{1,3}$", false},
+		{"[", true},
+		{"regex:(", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if err := ValidateNamePattern(tt.pattern); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNamePattern(%q) err = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseNamePatterns(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", []string{}},
+		{"nginx", []string{"nginx"}},
+		{"nginx, redis,,istio-*", []string{"nginx", "redis", "istio-*"}},
+		{"regex:^v[0-9]{1,3}$", []string{"regex:^v[0-9]{1,3}$"}},
+		{"nginx, regex:^(a|b),c$", []string{"nginx", "regex:^(a|b),c$"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ParseNamePatterns(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNamePatterns(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAnyName(t *testing.T) {
+	patterns := ParseNamePatterns("istio-proxy, regex:^(pause|POD)$")
+	for name, want := range map[string]bool{"istio-proxy": true, "POD": true, "nginx": false} {
+		if got := MatchAnyName(patterns, name); got != want {
+			t.Errorf("MatchAnyName(%q, %q) = %v, want %v", patterns, name, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+// The labels which are set to the containers by kubelet
+const (
+	KubernetesPodNamespaceLabel  = "io.kubernetes.pod.namespace"
+	KubernetesPodNameLabel       = "io.kubernetes.pod.name"
+	KubernetesPodUidLabel        = "io.kubernetes.pod.uid"
+	KubernetesContainerNameLabel = "io.kubernetes.container.name"
+
+	// KubernetesPodSandboxName is the container name of the sandbox created by dockershim and cri-dockerd
+	KubernetesPodSandboxName = "POD"
+	// ContainerdKindLabel is the label of the containers created by the cri plugin of containerd,
+	// the value is sandbox or container
+	ContainerdKindLabel   = "io.cri-containerd.kind"
+	ContainerdKindSandbox = "sandbox"
+	// DockerTypeLabel is the label of the containers created by dockershim and cri-dockerd,
+	// the value is podsandbox or container
+	DockerTypeLabel      = "io.kubernetes.docker.type"
+	DockerTypePodSandbox = "podsandbox"
+)
+
+// PodIdentity is the kubernetes pod of the container
+type PodIdentity struct {
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Uid           string `json:"uid,omitempty"`
+	ContainerName string `json:"containerName,omitempty"`
+}
+
+// GetPodIdentity returns the pod of the container by the kubernetes labels, nil if it is not a kubernetes container
+func GetPodIdentity(labels map[string]string) *PodIdentity {
+	if labels[KubernetesPodNameLabel] == "" {
+		return nil
+	}
+	return &PodIdentity{
+		Namespace:     labels[KubernetesPodNamespaceLabel],
+		Name:          labels[KubernetesPodNameLabel],
+		Uid:           labels[KubernetesPodUidLabel],
+		ContainerName: labels[KubernetesContainerNameLabel],
+	}
+}
+
+// IsSandbox returns true if the container is the pause container of a pod
+func IsSandbox(labels map[string]string) bool {
+	return labels[ContainerdKindLabel] == ContainerdKindSandbox ||
+		labels[DockerTypeLabel] == DockerTypePodSandbox ||
+		labels[KubernetesContainerNameLabel] == KubernetesPodSandboxName
+}
@@ -0,0 +1,477 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	containertype "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+const (
+	// DefaultEndpoint is the default unix socket address of the rootful podman service
+	DefaultEndpoint = "unix:///run/podman/podman.sock"
+	// rootlessSocket is the socket path of the rootless podman service which is relative to XDG_RUNTIME_DIR
+	rootlessSocket = "podman/podman.sock"
+
+	apiVersion        = "v4.0.0"
+	connectionTimeout = 2 * time.Second
+)
+
+type Client struct {
+	client   *http.Client
+	endpoint string
+	// key is the key of the client in the pool, the client is evicted by it if the connection is broken
+	key container.ClientKey
+
+	Ctx context.Context
+}
+
+// NewClient returns the podman client which talks to the libpod api over the unix socket,
+// the clients are cached by the endpoint
+func NewClient(endpoint string) (*Client, error) {
+	if endpoint == "" {
+		endpoint = defaultEndpoint()
+	}
+	key := container.ClientKey{Runtime: container.PodmanRuntime, Endpoint: endpoint}
+	cli, err := container.DefaultClientPool.Get(key, func() (container.PooledClient, error) {
+		cli, err := createClient(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		cli.key = key
+		return cli, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.(*Client), nil
+}
+
+// createClient returns the client of the endpoint and checks the podman service is serving
+func createClient(endpoint string) (*Client, error) {
+	socket := strings.TrimPrefix(endpoint, "unix://")
+	cli := &Client{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+		endpoint: endpoint,
+		Ctx:      context.Background(),
+	}
+	ctx, cancel := context.WithTimeout(cli.Ctx, connectionTimeout)
+	defer cancel()
+	if err := cli.Ping(ctx); err != nil {
+		cli.Close()
+		return nil, err
+	}
+	return cli, nil
+}
+
+// geteuid returns the effective user id, it is replaced by the tests
+var geteuid = os.Geteuid
+
+// defaultEndpoint returns the socket of the rootless podman service if the current user is not root
+func defaultEndpoint() string {
+	if geteuid() != 0 {
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			return "unix://" + path.Join(runtimeDir, rootlessSocket)
+		}
+	}
+	return DefaultEndpoint
+}
+
+// Ping checks the podman service is serving
+func (c *Client) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/_ping", nil, nil, nil)
+}
+
+// Close closes the idle connections to the podman service
+func (c *Client) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// apiError is the error response of the libpod api
+type apiError struct {
+	Cause    string `json:"cause"`
+	Message  string `json:"message"`
+	Response int    `json:"response"`
+}
+
+// request sends the request to the libpod api, the caller must close the body of the response
+func (c *Client) request(ctx context.Context, method, apiPath string, query url.Values, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	return c.requestWithReader(ctx, method, apiPath, query, reader, "application/json")
+}
+
+func (c *Client) requestWithReader(ctx context.Context, method, apiPath string, query url.Values, reader io.Reader,
+	contentType string,
+) (*http.Response, error) {
+	u := url.URL{Scheme: "http", Host: "d", Path: path.Join("/", apiVersion, "libpod", apiPath), RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		container.EvictOnConnectionError(c.key, c, err)
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var apiErr apiError
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("%s %s: %s", method, apiPath, apiErr.Message)
+		}
+		return nil, fmt.Errorf("%s %s: %s, %s", method, apiPath, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return resp, nil
+}
+
+// do sends the request and decodes the json response into out if it's not nil
+func (c *Client) do(ctx context.Context, method, apiPath string, query url.Values, body, out interface{}) error {
+	resp, err := c.request(ctx, method, apiPath, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// inspectContainer is the part of the libpod container inspect data used here
+type inspectContainer struct {
+	Id    string `json:"Id"`
+	Name  string `json:"Name"`
+	State struct {
+		Status    string `json:"Status"`
+		Running   bool   `json:"Running"`
+		Pid       int    `json:"Pid"`
+		ConmonPid int    `json:"ConmonPid"`
+	} `json:"State"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+// listContainer is the part of the libpod container list item used here
+type listContainer struct {
+	Id      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Labels  map[string]string `json:"Labels"`
+	State   string            `json:"State"`
+	Pid     int               `json:"Pid"`
+	Image   string            `json:"Image"`
+	Created time.Time         `json:"Created"`
+	// IsInfra is true for the infra container of a pod, which has no sandbox label
+	IsInfra bool `json:"IsInfra"`
+}
+
+// GetPidById returns the pid of the container process which is started and monitored by conmon
+func (c *Client) GetPidById(ctx context.Context, containerId string) (int32, error, int32) {
+	inspect, err := c.inspectContainer(ctx, containerId)
+	if err != nil {
+		return -1, errors.New(spec.ContainerExecFailed.Sprintf("ContainerInspect", err.Error())), spec.ContainerExecFailed.Code
+	}
+	if inspect.State.Pid <= 0 {
+		err := fmt.Errorf("the container is %s, conmon pid: %d", inspect.State.Status, inspect.State.ConmonPid)
+		return -1, errors.New(spec.ContainerExecFailed.Sprintf("ContainerInspect", err.Error())), spec.ContainerExecFailed.Code
+	}
+	return int32(inspect.State.Pid), nil, spec.OK.Code
+}
+
+func (c *Client) inspectContainer(ctx context.Context, containerId string) (inspectContainer, error) {
+	var inspect inspectContainer
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/containers/%s/json", containerId), nil, nil, &inspect)
+	return inspect, err
+}
+
+// GetContainerById returns the container by the id or the id prefix in any state
+func (c *Client) GetContainerById(ctx context.Context, containerId string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(ctx, container.ContainerFilter{ContainerId: containerId, IncludeSandbox: true})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerId, "container-id", containerId)
+}
+
+// GetContainerByName returns the container by any of its names
+func (c *Client) GetContainerByName(ctx context.Context, containerName string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(ctx, container.ContainerFilter{ContainerName: containerName})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerName, "container-name", containerName)
+}
+
+func (c *Client) GetContainerByLabelSelector(labels map[string]string) (container.ContainerInfo, error, int32) {
+	containers, err, code := c.GetContainers(c.Ctx, container.ContainerFilter{Labels: labels})
+	if err != nil {
+		return container.ContainerInfo{}, err, code
+	}
+	return container.SelectContainer(containers, spec.ParameterInvalidDockContainerId, "container-label-selector",
+		container.FormatLabels(labels))
+}
+
+func (c *Client) listContainers(ctx context.Context, all bool, filters map[string][]string) ([]listContainer, error) {
+	query := url.Values{}
+	query.Set("all", fmt.Sprintf("%t", all))
+	if len(filters) > 0 {
+		data, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(data))
+	}
+	containers := make([]listContainer, 0)
+	err := c.do(ctx, http.MethodGet, "/containers/json", query, nil, &containers)
+	return containers, err
+}
+
+func convertContainerInfo(ctr listContainer) container.ContainerInfo {
+	var name string
+	if len(ctr.Names) > 0 {
+		name = ctr.Names[0]
+	}
+	return container.ContainerInfo{
+		ContainerId:   ctr.Id,
+		ContainerName: name,
+		Labels:        ctr.Labels,
+		State:         container.ParseState(ctr.State),
+		Pid:           int32(ctr.Pid),
+		Image:         ctr.Image,
+		Created:       ctr.Created,
+		Runtime:       container.PodmanRuntime,
+		Sandbox:       ctr.IsInfra,
+	}
+}
+
+// GetContainers returns all the containers matched the filter, the stopped containers are contained
+func (c *Client) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	filters := make(map[string][]string)
+	if filter.ContainerId != "" {
+		filters["id"] = []string{filter.ContainerId}
+	}
+	for k, v := range filter.Labels {
+		filters["label"] = append(filters["label"], fmt.Sprintf("%s=%s", k, v))
+	}
+	if filter.Image != "" && !container.IsImageRegexp(filter.Image) {
+		filters["ancestor"] = []string{filter.Image}
+	}
+	containers, err := c.listContainers(ctx, true, filters)
+	if err != nil {
+		return nil, errors.New(spec.ContainerExecFailed.Sprintf("GetContainerList", err.Error())), spec.ContainerExecFailed.Code
+	}
+	result := make([]container.ContainerInfo, 0, len(containers))
+	for _, ctr := range containers {
+		if !matchAnyName(filter, ctr) {
+			continue
+		}
+		result = append(result, convertContainerInfo(ctr))
+	}
+	return result, nil, spec.OK.Code
+}
+
+// matchAnyName returns true if the filter matches the container by any name of the container
+func matchAnyName(filter container.ContainerFilter, ctr listContainer) bool {
+	info := convertContainerInfo(ctr)
+	if len(ctr.Names) == 0 {
+		return filter.Match(info)
+	}
+	for _, name := range ctr.Names {
+		info.ContainerName = name
+		if filter.Match(info) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveContainer
+func (c *Client) RemoveContainer(ctx context.Context, containerId string, force bool) error {
+	query := url.Values{}
+	query.Set("force", fmt.Sprintf("%t", force))
+	err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/containers/%s", containerId), query, nil, nil)
+	if err != nil {
+		log.Warnf(ctx, "Remove container: %s, err: %s", containerId, err)
+		return err
+	}
+	return nil
+}
+
+// ExecContainer executes the command in the container by the exec api of libpod, which works for the rootless podman
+// without entering the user namespace of the container
+func (c *Client) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	log.Infof(ctx, "exec container cmd: %s, container: %s", command, containerId)
+	start := time.Now()
+	var created struct {
+		Id string `json:"Id"`
+	}
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/containers/%s/exec", containerId), nil, map[string]interface{}{
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"Cmd":          []string{"/bin/sh", "-c", command},
+		"Privileged":   true,
+		"User":         "root",
+	}, &created)
+	if err != nil {
+		log.Warnf(ctx, "Create exec for container: %s, err: %s", containerId, err.Error())
+		return container.ExecResult{}, err
+	}
+	resp, err := c.request(ctx, http.MethodPost, fmt.Sprintf("/exec/%s/start", created.Id), nil, map[string]interface{}{
+		"Detach": false,
+		"Tty":    false,
+	})
+	if err != nil {
+		log.Warnf(ctx, "Start exec for container: %s, err: %s", containerId, err.Error())
+		return container.ExecResult{}, err
+	}
+	defer resp.Body.Close()
+	result, err := container.ReadExecResult(resp.Body, start, func() (int, error) {
+		var inspect struct {
+			ExitCode int `json:"ExitCode"`
+		}
+		err := c.do(ctx, http.MethodGet, fmt.Sprintf("/exec/%s/json", created.Id), nil, nil, &inspect)
+		return inspect.ExitCode, err
+	})
+	if err != nil {
+		log.Warnf(ctx, "Exec in container: %s, err: %s", containerId, err.Error())
+		return result, err
+	}
+	log.Debugf(ctx, "Command Result, output: %s, errMsg: %s, exitCode: %d", result.Stdout, result.Stderr, result.ExitCode)
+	return result, nil
+}
+
+// CopyToContainer copies a tar file to the dstPath by the archive api of libpod, the compressed tar is extracted
+// by the podman service
+func (c *Client) CopyToContainer(ctx context.Context, containerId, srcFile, dstPath, extractDirName string, override bool) error {
+	result, err := c.ExecContainer(ctx, containerId, container.ShellJoin("mkdir", "-p", dstPath))
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+	file, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	query := url.Values{}
+	query.Set("path", dstPath)
+	resp, err := c.requestWithReader(ctx, http.MethodPut, fmt.Sprintf("/containers/%s/archive", containerId),
+		query, file, "application/x-tar")
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ExecuteAndRemove creates a container which joins the network namespace of the target container, executes the
+// command in it, and removes the container
+func (c *Client) ExecuteAndRemove(ctx context.Context, config *containertype.Config, hostConfig *containertype.HostConfig,
+	networkConfig *network.NetworkingConfig, containerName string, removed bool, timeout time.Duration,
+	command string, containerInfo container.ContainerInfo,
+) (containerId string, result container.ExecResult, err error, code int32) {
+	log.Debugf(ctx, "command: '%s', image: %s, containerName: %s", command, config.Image, containerName)
+	if err := c.pullImageIfNotPresent(ctx, config.Image); err != nil {
+		return "", result, errors.New(spec.ImagePullFailed.Sprintf(config.Image, err)), spec.ImagePullFailed.Code
+	}
+	var capAdd []string
+	if hostConfig != nil {
+		capAdd = hostConfig.CapAdd
+	}
+	var created struct {
+		Id string `json:"Id"`
+	}
+	err = c.do(ctx, http.MethodPost, "/containers/create", nil, map[string]interface{}{
+		"name":     containerName,
+		"image":    config.Image,
+		"command":  config.Cmd,
+		"labels":   config.Labels,
+		"terminal": config.Tty,
+		"stdin":    true,
+		"cap_add":  capAdd,
+		"netns": map[string]string{
+			"nsmode": "container",
+			"value":  containerInfo.ContainerId,
+		},
+	}, &created)
+	if err != nil {
+		return "", result, errors.New(spec.CreateContainerFailed.Sprintf(err)), spec.CreateContainerFailed.Code
+	}
+	containerId = created.Id
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/containers/%s/start", containerId), nil, nil, nil); err != nil {
+		c.RemoveContainer(ctx, containerId, true)
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("CreateAndStartContainer", err)), spec.ContainerExecFailed.Code
+	}
+
+	result, err = c.ExecContainer(ctx, containerId, command)
+	if removed {
+		c.RemoveContainer(ctx, containerId, true)
+	}
+	if err != nil {
+		return containerId, result, errors.New(spec.ContainerExecFailed.Sprintf("ContainerExecCmd", err)), spec.ContainerExecFailed.Code
+	}
+	log.Infof(ctx, "Execute output in container: %s, exit code: %d", result.Output(), result.ExitCode)
+	return containerId, result, nil, spec.OK.Code
+}
+
+func (c *Client) pullImageIfNotPresent(ctx context.Context, ref string) error {
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/images/%s/exists", ref), nil, nil, nil); err == nil {
+		return nil
+	}
+	query := url.Values{}
+	query.Set("reference", ref)
+	return c.do(ctx, http.MethodPost, "/images/pull", query, nil, nil)
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeLibpod serves the part of the libpod api used by the client
+type fakeLibpod struct {
+	containers []listContainer
+	inspects   map[string]inspectContainer
+	exitCode   int
+	cmd        []string
+}
+
+func (f *fakeLibpod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apiPath := strings.TrimPrefix(r.URL.Path, "/"+apiVersion+"/libpod")
+	switch {
+	case apiPath == "/_ping":
+		fmt.Fprint(w, "OK")
+	case apiPath == "/containers/json":
+		json.NewEncoder(w).Encode(f.containers)
+	case strings.HasPrefix(apiPath, "/containers/") && strings.HasSuffix(apiPath, "/json"):
+		id := strings.TrimSuffix(strings.TrimPrefix(apiPath, "/containers/"), "/json")
+		inspect, ok := f.inspects[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"cause":"no such container","message":"no container with name or ID %q found: no such container","response":404}`, id)
+			return
+		}
+		json.NewEncoder(w).Encode(inspect)
+	case strings.HasPrefix(apiPath, "/containers/") && strings.HasSuffix(apiPath, "/exec"):
+		var body struct {
+			Cmd []string `json:"Cmd"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.cmd = body.Cmd
+		fmt.Fprint(w, `{"Id":"exec1"}`)
+	case apiPath == "/exec/exec1/start":
+		stdcopy.NewStdWriter(w, stdcopy.Stdout).Write([]byte("out"))
+		stdcopy.NewStdWriter(w, stdcopy.Stderr).Write([]byte("err"))
+	case apiPath == "/exec/exec1/json":
+		fmt.Fprintf(w, `{"ExitCode":%d}`, f.exitCode)
+	case apiPath == "/images/busybox/exists":
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "internal error")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func startFakeLibpod(t *testing.T, libpod *fakeLibpod) *Client {
+	cli, _ := startFakeLibpodServer(t, libpod)
+	return cli
+}
+
+func startFakeLibpodServer(t *testing.T, libpod *fakeLibpod) (*Client, *httptest.Server) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "podman.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen %s: %v", socket, err)
+	}
+	server := httptest.NewUnstartedServer(libpod)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	cli, err := NewClient("unix://" + socket)
+	if err != nil {
+		t.Fatalf("NewClient(%s): %v", socket, err)
+	}
+	t.Cleanup(func() { container.DefaultClientPool.CloseAll() })
+	return cli, server
+}
+
+func testLibpod() *fakeLibpod {
+	libpod := &fakeLibpod{
+		containers: []listContainer{
+			{Id: "abc123", Names: []string{"nginx", "web"}, State: "running", Pid: 1234, Labels: map[string]string{"app": "nginx"}},
+			{Id: "abd456", Names: []string{"redis"}, State: "exited", Labels: map[string]string{"app": "redis"}},
+			{Id: "fff789", Names: []string{"f00d-infra"}, State: "running", Pid: 1000, IsInfra: true},
+		},
+		inspects: map[string]inspectContainer{},
+	}
+	running := inspectContainer{Id: "abc123"}
+	running.State.Status = "running"
+	running.State.Pid = 1234
+	libpod.inspects["abc123"] = running
+	exited := inspectContainer{Id: "abd456"}
+	exited.State.Status = "exited"
+	exited.State.ConmonPid = 999
+	libpod.inspects["abd456"] = exited
+	return libpod
+}
+
+func TestDefaultEndpoint(t *testing.T) {
+	defer func(uid func() int) { geteuid = uid }(geteuid)
+	tests := []struct {
+		name       string
+		uid        int
+		runtimeDir string
+		want       string
+	}{
+		{"root", 0, "/run/user/0", DefaultEndpoint},
+		{"rootless", 1000, "/run/user/1000", "unix:///run/user/1000/podman/podman.sock"},
+		{"rootless without the runtime dir", 1000, "", DefaultEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geteuid = func() int { return tt.uid }
+			t.Setenv("XDG_RUNTIME_DIR", tt.runtimeDir)
+			if got := defaultEndpoint(); got != tt.want {
+				t.Errorf("defaultEndpoint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetContainers(t *testing.T) {
+	cli := startFakeLibpod(t, testLibpod())
+	tests := []struct {
+		name   string
+		filter container.ContainerFilter
+		want   []string
+	}{
+		{"the infra container is skipped", container.ContainerFilter{}, []string{"abc123", "abd456"}},
+		{"include sandbox", container.ContainerFilter{IncludeSandbox: true}, []string{"abc123", "abd456", "fff789"}},
+		{"by the first name", container.ContainerFilter{ContainerName: "nginx"}, []string{"abc123"}},
+		{"by the other name", container.ContainerFilter{ContainerName: "web"}, []string{"abc123"}},
+		{"by the name pattern", container.ContainerFilter{NamePattern: "we*"}, []string{"abc123"}},
+		{"by labels", container.ContainerFilter{Labels: map[string]string{"app": "redis"}}, []string{"abd456"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err, _ := cli.GetContainers(cli.Ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetContainers() err = %v", err)
+			}
+			ids := make([]string, 0, len(containers))
+			for _, ctr := range containers {
+				ids = append(ids, ctr.ContainerId)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("GetContainers() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientGetContainerById(t *testing.T) {
+	cli := startFakeLibpod(t, testLibpod())
+	tests := []struct {
+		containerId string
+		want        string
+		code        int32
+	}{
+		{"abc", "abc123", spec.OK.Code},
+		{"fff", "fff789", spec.OK.Code},
+		{"ab", "", spec.ParameterInvalid.Code},
+		{"xyz", "", spec.ParameterInvalidDockContainerId.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.containerId, func(t *testing.T) {
+			info, err, code := cli.GetContainerById(cli.Ctx, tt.containerId)
+			if code != tt.code || info.ContainerId != tt.want {
+				t.Errorf("GetContainerById(%s) = %s, %v, %d, want %s, code %d", tt.containerId, info.ContainerId, err,
+					code, tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestClientGetPidById(t *testing.T) {
+	cli := startFakeLibpod(t, testLibpod())
+	if pid, err, _ := cli.GetPidById(cli.Ctx, "abc123"); err != nil || pid != 1234 {
+		t.Errorf("GetPidById(abc123) = %d, %v, want 1234", pid, err)
+	}
+	_, err, code := cli.GetPidById(cli.Ctx, "abd456")
+	if err == nil || code != spec.ContainerExecFailed.Code || !strings.Contains(err.Error(), "conmon pid: 999") {
+		t.Errorf("GetPidById(abd456) = %v, code %d, want the failure of the stopped container", err, code)
+	}
+}
+
+func TestClientErrorDecoding(t *testing.T) {
+	cli := startFakeLibpod(t, testLibpod())
+	_, err, _ := cli.GetPidById(cli.Ctx, "missing")
+	if err == nil || !strings.Contains(err.Error(), `no container with name or ID "missing" found`) {
+		t.Errorf("GetPidById(missing) err = %v, want the message of the libpod error", err)
+	}
+	err = cli.do(cli.Ctx, http.MethodGet, "/images/busybox/exists", nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "500 Internal Server Error, internal error") {
+		t.Errorf("do() err = %v, want the status and the body of the non-json error", err)
+	}
+}
+
+func TestClientExecContainer(t *testing.T) {
+	libpod := testLibpod()
+	libpod.exitCode = 3
+	cli := startFakeLibpod(t, libpod)
+	result, err := cli.ExecContainer(cli.Ctx, "abc123", "echo out")
+	if err != nil {
+		t.Fatalf("ExecContainer() err = %v", err)
+	}
+	if strings.Join(libpod.cmd, " ") != "/bin/sh -c echo out" {
+		t.Errorf("ExecContainer() cmd = %q", libpod.cmd)
+	}
+	if result.Stdout != "out" || result.Stderr != "err" || result.ExitCode != 3 {
+		t.Errorf("ExecContainer() = %+v, want stdout out, stderr err, exit code 3", result)
+	}
+}
+
+func TestClientEvictedOnConnectionError(t *testing.T) {
+	cli, server := startFakeLibpodServer(t, testLibpod())
+	if _, err, _ := cli.GetContainers(cli.Ctx, container.ContainerFilter{}); err != nil {
+		t.Fatalf("GetContainers() err = %v", err)
+	}
+	server.Close()
+	if _, err, _ := cli.GetContainers(cli.Ctx, container.ContainerFilter{}); err == nil {
+		t.Fatal("GetContainers() of the stopped service succeeds")
+	}
+	created := false
+	_, err := container.DefaultClientPool.Get(cli.key, func() (container.PooledClient, error) {
+		created = true
+		return &Client{client: &http.Client{}}, nil
+	})
+	if err != nil || !created {
+		t.Errorf("the broken client is not evicted, created: %v, err: %v", created, err)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultHealthCheckInterval is the interval of checking the health of a pooled client
+const DefaultHealthCheckInterval = 30 * time.Second
+
+const healthCheckTimeout = 2 * time.Second
+
+// PooledClient is the runtime client which can be cached by the ClientPool
+type PooledClient interface {
+	// Ping checks the connection to the runtime is healthy
+	Ping(ctx context.Context) error
+	// Close releases the connection to the runtime
+	Close() error
+}
+
+// ClientKey identifies a runtime client, the clients of different endpoints or namespaces are never shared
+type ClientKey struct {
+	Runtime   string
+	Endpoint  string
+	Namespace string
+}
+
+// pooledEntry is locked while its client is checked or created, so the clients of the other keys are not blocked
+type pooledEntry struct {
+	mu        sync.Mutex
+	client    PooledClient
+	checkedAt time.Time
+	// removed is true if the entry is removed from the pool, the waiting callers look up the key again
+	removed bool
+}
+
+// ClientPool caches the runtime clients by the runtime, endpoint and namespace. It is safe for concurrent use
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[ClientKey]*pooledEntry
+	// retired are the evicted clients which may still be used by the callers, they are closed by CloseAll
+	retired []PooledClient
+
+	// HealthCheckInterval is the interval of checking a cached client, the client is checked on every Get if it is zero
+	HealthCheckInterval time.Duration
+}
+
+// DefaultClientPool is the pool used by the runtime clients
+var DefaultClientPool = NewClientPool(DefaultHealthCheckInterval)
+
+// NewClientPool returns an empty client pool
+func NewClientPool(healthCheckInterval time.Duration) *ClientPool {
+	return &ClientPool{
+		clients:             make(map[ClientKey]*pooledEntry),
+		HealthCheckInterval: healthCheckInterval,
+	}
+}
+
+// Get returns the cached client of the key, the client is created by the create function if it does not exist
+// or is broken. The health of a cached client is checked lazily, at most once every HealthCheckInterval.
+// The concurrent calls of the same key wait for one check or creation, the other keys are not blocked
+func (p *ClientPool) Get(key ClientKey, create func() (PooledClient, error)) (PooledClient, error) {
+	entry := p.lockEntry(key)
+	defer entry.mu.Unlock()
+	if entry.client != nil {
+		if time.Since(entry.checkedAt) < p.HealthCheckInterval {
+			return entry.client, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		err := entry.client.Ping(ctx)
+		cancel()
+		if err == nil {
+			entry.checkedAt = time.Now()
+			return entry.client, nil
+		}
+		// the broken client may still be used by the callers which got it before, so it is retired but not closed
+		p.retire(entry.client)
+		entry.client = nil
+	}
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	entry.client = client
+	entry.checkedAt = time.Now()
+	return client, nil
+}
+
+// lockEntry returns the locked entry of the key, the entry is added if it does not exist
+func (p *ClientPool) lockEntry(key ClientKey) *pooledEntry {
+	for {
+		p.mu.Lock()
+		entry, ok := p.clients[key]
+		if !ok {
+			entry = &pooledEntry{}
+			p.clients[key] = entry
+		}
+		p.mu.Unlock()
+
+		entry.mu.Lock()
+		if !entry.removed {
+			return entry
+		}
+		entry.mu.Unlock()
+	}
+}
+
+// Evict removes the client of the key if it is still cached, the next Get creates a new one. The clients call it
+// when the runtime connection is broken, the evicted client is closed by CloseAll
+func (p *ClientPool) Evict(key ClientKey, client PooledClient) {
+	p.mu.Lock()
+	entry, ok := p.clients[key]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	// the client may be replaced by a concurrent Get, the new one is kept
+	if entry.client == nil || entry.client != client {
+		return
+	}
+	p.retire(entry.client)
+	entry.client = nil
+}
+
+// EvictOnConnectionError evicts the client of the key from the default pool if the error is a connection error
+func EvictOnConnectionError(key ClientKey, client PooledClient, err error) {
+	if IsConnectionError(err) {
+		DefaultClientPool.Evict(key, client)
+	}
+}
+
+// IsConnectionError returns true if the error is caused by the broken connection to the runtime
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if status.Code(err) == codes.Unavailable {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+func (p *ClientPool) retire(client PooledClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retired = append(p.retired, client)
+}
+
+// CloseAll closes all the cached and the retired clients and empties the pool, the clients must not be used after it.
+// The runtime clients live as long as the process, so it is only called by the tests and the embedding programs
+func (p *ClientPool) CloseAll() error {
+	p.mu.Lock()
+	entries := p.clients
+	clients := p.retired
+	p.clients = make(map[ClientKey]*pooledEntry)
+	p.retired = nil
+	p.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.mu.Lock()
+		entry.removed = true
+		if entry.client != nil {
+			clients = append(clients, entry.client)
+			entry.client = nil
+		}
+		entry.mu.Unlock()
+	}
+	var errs []error
+	for _, client := range clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
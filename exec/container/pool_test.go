@@ -0,0 +1,183 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePooledClient struct {
+	pingErr error
+	closed  atomic.Bool
+}
+
+func (c *fakePooledClient) Ping(ctx context.Context) error {
+	return c.pingErr
+}
+
+func (c *fakePooledClient) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestClientPoolGet(t *testing.T) {
+	pool := NewClientPool(time.Hour)
+	key := ClientKey{Runtime: DockerRuntime}
+	created := 0
+	create := func() (PooledClient, error) {
+		created++
+		return &fakePooledClient{}, nil
+	}
+	first, err := pool.Get(key, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pool.Get(key, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || created != 1 {
+		t.Errorf("the client is created %d times, want the cached one", created)
+	}
+	if _, err := pool.Get(key, func() (PooledClient, error) { return nil, errors.New("failed") }); err != nil {
+		t.Errorf("the cached client is not returned, %v", err)
+	}
+}
+
+func TestClientPoolKeysNotBlocked(t *testing.T) {
+	pool := NewClientPool(time.Hour)
+	creating := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = pool.Get(ClientKey{Runtime: CriRuntime}, func() (PooledClient, error) {
+			close(creating)
+			<-release
+			return &fakePooledClient{}, nil
+		})
+	}()
+	<-creating
+	defer close(release)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.Get(ClientKey{Runtime: DockerRuntime}, func() (PooledClient, error) {
+			return &fakePooledClient{}, nil
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the client of another key is blocked by the creating one")
+	}
+}
+
+func TestClientPoolRetire(t *testing.T) {
+	pool := NewClientPool(0)
+	key := ClientKey{Runtime: ContainerdRuntime}
+	broken := &fakePooledClient{pingErr: errors.New("broken")}
+	if _, err := pool.Get(key, func() (PooledClient, error) { return broken, nil }); err != nil {
+		t.Fatal(err)
+	}
+	healthy := &fakePooledClient{}
+	client, err := pool.Get(key, func() (PooledClient, error) { return healthy, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != healthy {
+		t.Fatal("the broken client is not replaced")
+	}
+	evicted := &fakePooledClient{}
+	evictedKey := ClientKey{Runtime: PodmanRuntime}
+	if _, err := pool.Get(evictedKey, func() (PooledClient, error) { return evicted, nil }); err != nil {
+		t.Fatal(err)
+	}
+	pool.Evict(evictedKey, evicted)
+	if broken.closed.Load() || evicted.closed.Load() {
+		t.Fatal("the replaced clients are closed while they may be used")
+	}
+	if err := pool.CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+	for name, client := range map[string]*fakePooledClient{"broken": broken, "evicted": evicted, "healthy": healthy} {
+		if !client.closed.Load() {
+			t.Errorf("the %s client is not closed", name)
+		}
+	}
+}
+
+func TestClientPoolEvict(t *testing.T) {
+	pool := NewClientPool(time.Hour)
+	key := ClientKey{Runtime: DockerRuntime}
+	stale := &fakePooledClient{}
+	if _, err := pool.Get(key, func() (PooledClient, error) { return stale, nil }); err != nil {
+		t.Fatal(err)
+	}
+	pool.Evict(key, stale)
+	current := &fakePooledClient{}
+	client, err := pool.Get(key, func() (PooledClient, error) { return current, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != current {
+		t.Fatal("the evicted client is returned")
+	}
+	// the stale client evicts itself again after it is replaced, the current one must be kept
+	pool.Evict(key, stale)
+	client, err = pool.Get(key, func() (PooledClient, error) { return &fakePooledClient{}, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != current {
+		t.Error("the current client is evicted by the stale one")
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"grpc unavailable", status.Error(codes.Unavailable, "connection refused"), true},
+		{"grpc not found", status.Error(codes.NotFound, "container not found"), false},
+		{"dial", &net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}, true},
+		{"wrapped refused", fmt.Errorf("list containers: %w", syscall.ECONNREFUSED), true},
+		{"other", errors.New("no such container"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnectionError(tt.err); got != tt.want {
+				t.Errorf("IsConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"strings"
+)
+
+// ShellQuote quotes the value as one word of /bin/sh, the value is returned as it is if it is safe
+func ShellQuote(value string) string {
+	if value == "" {
+		return "''"
+	}
+	safe := true
+	for _, c := range value {
+		if !isShellSafe(c) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+// ShellJoin returns the command line of /bin/sh -c which executes the argv exactly
+func ShellJoin(args ...string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, ShellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func isShellSafe(c rune) bool {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+		return true
+	}
+	return strings.ContainsRune("@%+=:,./-_", c)
+}
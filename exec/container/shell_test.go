@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "''"},
+		{"fullload", "fullload"},
+		{"--cpu-percent=50", "--cpu-percent=50"},
+		{"/opt/chaosblade/bin/chaos_os", "/opt/chaosblade/bin/chaos_os"},
+		{"a b", "'a b'"},
+		{"it's", `'it'"'"'s'`},
+		{`"$HOME"`, `'"$HOME"'`},
+		{"a;rm -rf /", "'a;rm -rf /'"},
+		{"$(id)", "'$(id)'"},
+		{"line\nbreak", "'line\nbreak'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ShellQuote(tt.value); got != tt.want {
+				t.Errorf("ShellQuote(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not found")
+	}
+	args := []string{"create", "--script=echo \"a b\" 'c'", "", "$(id)", "`id`", "a;b|c&d", "line\nbreak", "*", "~"}
+	output, err := exec.Command(sh, "-c", "printf '%s\\0' "+ShellJoin(args...)).Output()
+	if err != nil {
+		t.Fatalf("sh -c err = %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("the argv executed by sh = %q, want %q", got, args)
+	}
+}
@@ -0,0 +1,171 @@
+//go:build linux || darwin
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// DestroyAllReport is the result of destroying all the cri experiments on the node
+type DestroyAllReport struct {
+	Experiments []DestroyReport `json:"experiments"`
+	// RemovedSidecars are the ids of the leftover sidecar containers which are removed
+	RemovedSidecars []string `json:"removedSidecars,omitempty"`
+	// Errors are the failures which do not belong to any experiment
+	Errors []string `json:"errors,omitempty"`
+}
+
+// DestroyReport is the result of destroying one cri experiment
+type DestroyReport struct {
+	Uid    string `json:"uid"`
+	Target string `json:"target"`
+	Action string `json:"action"`
+	// KilledPids are the hang processes which are killed
+	KilledPids []int  `json:"killedPids,omitempty"`
+	Success    bool   `json:"success"`
+	Err        string `json:"error,omitempty"`
+}
+
+// DestroyAllExperiments reverts every cri experiment on the node regardless of the flags, and returns the report.
+// It continues past the failures and can be invoked again to retry the failed experiments. The revert watchers of the
+// failed experiments are resumed afterwards, they are not resumed before because the watchers would race with it
+func DestroyAllExperiments(ctx context.Context) *spec.Response {
+	report, err := DestroyAll(ctx)
+	resumeRevertWatchers(ctx)
+	if err != nil {
+		log.Errorf(ctx, "%s", spec.DbQueryFailed.Sprintf("experiment states", err))
+		return spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiment states", err)
+	}
+	failures := make([]string, 0)
+	for _, experiment := range report.Experiments {
+		if !experiment.Success {
+			failures = append(failures, fmt.Sprintf("%s: %s", experiment.Uid, experiment.Err))
+		}
+	}
+	failures = append(failures, report.Errors...)
+	if len(failures) == 0 {
+		return spec.ReturnSuccess(report)
+	}
+	return &spec.Response{
+		Code:    spec.ContainerExecFailed.Code,
+		Success: false,
+		Err:     fmt.Sprintf("destroy all the experiments failed, %s", strings.Join(failures, "; ")),
+		Result:  report,
+	}
+}
+
+// DestroyAll kills the hang processes, destroys the experiments by their executors and removes the leftover sidecars
+func DestroyAll(ctx context.Context) (DestroyAllReport, error) {
+	states, err := ListExperimentStates()
+	if err != nil {
+		return DestroyAllReport{}, err
+	}
+	report := DestroyAllReport{
+		Experiments: make([]DestroyReport, 0, len(states)),
+	}
+	modelSpec := NewCriExpModelSpec()
+	for _, state := range states {
+		report.Experiments = append(report.Experiments, destroyExperiment(ctx, modelSpec, state))
+	}
+	report.RemovedSidecars, report.Errors = removeSidecars(ctx, states)
+	return report, nil
+}
+
+func destroyExperiment(ctx context.Context, modelSpec *DockerExpModelSpec, state *ExperimentState) DestroyReport {
+	report := DestroyReport{
+		Uid:    state.Uid,
+		Target: state.Target,
+		Action: state.Action,
+	}
+	failures := make([]string, 0)
+	for _, target := range state.Containers {
+		killed, err := killExperimentProcess(target.HangPid, state.Uid)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("kill the hang process %d failed, %v", target.HangPid, err))
+		} else if killed {
+			report.KilledPids = append(report.KilledPids, target.HangPid)
+		}
+	}
+	action := modelSpec.GetExpActionModelSpec(state.Target, state.Action)
+	if action == nil || action.Executor() == nil {
+		failures = append(failures, fmt.Sprintf("the executor of %s %s not found", state.Target, state.Action))
+	} else {
+		response := action.Executor().Exec(state.Uid, spec.SetDestroyFlag(ctx, state.Uid), stateExpModel(state, state.Flags))
+		if !response.Success {
+			failures = append(failures, response.Err)
+		}
+	}
+	if len(failures) > 0 {
+		report.Err = strings.Join(failures, "; ")
+		log.Errorf(ctx, "destroy the experiment %s failed, %s", state.Uid, report.Err)
+		return report
+	}
+	if err := RemoveExperimentState(state.Uid); err != nil {
+		log.Warnf(ctx, "remove the state of %s failed, %v", state.Uid, err)
+	}
+	report.Success = true
+	return report
+}
+
+// removeSidecars removes the sidecar containers in the runtimes of the experiments and in the default docker
+func removeSidecars(ctx context.Context, states []*ExperimentState) ([]string, []string) {
+	removed := make([]string, 0)
+	failures := make([]string, 0)
+	visited := make(map[container.ClientKey]bool)
+	candidates := append([]*ExperimentState{{Runtime: container.DockerRuntime}}, states...)
+	for idx, state := range candidates {
+		key := container.ClientKey{Runtime: state.Runtime, Endpoint: state.Endpoint, Namespace: state.Namespace}
+		if state.Runtime == "" || visited[key] {
+			continue
+		}
+		visited[key] = true
+		client, err := stateClient(ctx, state)
+		if err != nil {
+			// the default docker is not required to be running
+			if idx > 0 {
+				failures = append(failures, fmt.Sprintf("connect to %s failed, %v", state.Runtime, err))
+			}
+			continue
+		}
+		sidecars, err, _ := client.GetContainers(ctx, container.ContainerFilter{
+			Labels: map[string]string{SidecarLabelKey: SidecarLabelValue},
+		})
+		if err != nil {
+			if idx > 0 {
+				failures = append(failures, fmt.Sprintf("list the sidecars in %s failed, %v", state.Runtime, err))
+			}
+			continue
+		}
+		for _, sidecar := range sidecars {
+			if err := client.RemoveContainer(container.WithTargetNamespace(ctx, sidecar.Namespace), sidecar.ContainerId, true); err != nil {
+				failures = append(failures, fmt.Sprintf("remove the sidecar %s failed, %v", sidecar.ContainerId, err))
+				continue
+			}
+			removed = append(removed, sidecar.ContainerId)
+		}
+	}
+	return removed, failures
+}
@@ -18,8 +18,11 @@ package exec
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -37,11 +40,57 @@ const (
 type BaseClientExecutor struct {
 	Client      container.Container
 	CommandFunc func(uid string, ctx context.Context, model *spec.ExpModel) string
+	// Kind is the kind of the executor which is recorded in the experiment state
+	Kind string
 }
 
-// SetClient to the executor
+// ExperimentClient is the runtime client prepared for an experiment by PrepareClient. The executors are shared by
+// the concurrent experiments, so the client is passed along instead of being set to the executor
+type ExperimentClient struct {
+	container.Container
+	// Detection is not nil if the container runtime is detected automatically
+	Detection *RuntimeDetection
+}
+
+// RuntimeDetection is the container runtime and the endpoint chosen when the container-runtime flag is omitted
+type RuntimeDetection struct {
+	Runtime  string `json:"runtime"`
+	Endpoint string `json:"endpoint"`
+}
+
+// ExperimentResult is the response result which contains the context of the experiment beside the result of the
+// executor. The fields which do not apply are omitted, and the result is not wrapped if none of them applies
+type ExperimentResult struct {
+	// Runtime and Endpoint are the container runtime detected when the container-runtime flag is omitted
+	Runtime  string `json:"runtime,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Pod is the kubernetes pod of the container targeted by the pod flags
+	Pod *container.PodIdentity `json:"pod,omitempty"`
+	// RevertTime is the time when the experiment is reverted automatically
+	RevertTime *time.Time  `json:"revertTime,omitempty"`
+	Lease      string      `json:"lease,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+}
+
+// withExperimentResult wraps the result of the response by the ExperimentResult if it is not, and then sets it
+func withExperimentResult(response *spec.Response, set func(result *ExperimentResult)) *spec.Response {
+	if response == nil {
+		return nil
+	}
+	result, ok := response.Result.(*ExperimentResult)
+	if !ok {
+		result = &ExperimentResult{Result: response.Result}
+		response.Result = result
+	}
+	set(result)
+	return response
+}
+
+// SetClient to the executor, it is not safe if the executor is shared by the concurrent experiments.
+//
+// Deprecated: use PrepareClient which returns the client of the experiment
 func (b *BaseClientExecutor) SetClient(expModel *spec.ExpModel) error {
-	cli, err := GetClientByRuntime(expModel)
+	cli, _, err := getClient(context.Background(), expModel)
 	if err != nil {
 		return err
 	}
@@ -49,94 +98,409 @@ func (b *BaseClientExecutor) SetClient(expModel *spec.ExpModel) error {
 	return nil
 }
 
-// commonFunc is the command created function
-var CommonFunc = func(uid string, ctx context.Context, model *spec.ExpModel) string {
-	matchers := spec.ConvertExpMatchersToString(model, func() map[string]spec.Empty {
-		return GetAllDockerFlagNames()
+// WithDetection adds the detected container runtime to the response
+func (c *ExperimentClient) WithDetection(response *spec.Response) *spec.Response {
+	return withDetection(c.Detection, response)
+}
+
+func withDetection(detection *RuntimeDetection, response *spec.Response) *spec.Response {
+	if detection == nil {
+		return response
+	}
+	return withExperimentResult(response, func(result *ExperimentResult) {
+		result.Runtime = detection.Runtime
+		result.Endpoint = detection.Endpoint
 	})
+}
+
+// ResponseMarker is printed in a line before the chaosblade command created by CommonFunc is executed, the response
+// is decoded from the stdout after the marker, so the output of the shell profiles is never taken as the response
+const ResponseMarker = "CHAOSBLADE_RESPONSE"
+
+// commonFunc is the command created function, the flag values are quoted for /bin/sh
+var CommonFunc = func(uid string, ctx context.Context, model *spec.ExpModel) string {
 	if _, ok := spec.IsDestroy(ctx); ok {
 		// UPDATE: https://github.com/chaosblade-io/chaosblade/issues/334
-		return fmt.Sprintf("%s destroy %s %s %s", BladeBin, model.Target, model.ActionName, matchers)
+		args := NewArgs(BladeBin, spec.Destroy, model.Target, model.ActionName).Flags(model.ActionFlags, GetAllDockerFlagNames())
+		return withResponseMarker(container.ShellJoin(args.Argv()...))
 	}
-	return fmt.Sprintf("%s create %s %s %s --uid %s", BladeBin, model.Target, model.ActionName, matchers, uid)
+	args := NewArgs(BladeBin, spec.Create, model.Target, model.ActionName).Flags(model.ActionFlags, GetAllDockerFlagNames())
+	return withResponseMarker(container.ShellJoin(args.Add("--uid", uid).Argv()...))
 }
 
-func ConvertContainerOutputToResponse(output string, err error, defaultResponse *spec.Response) *spec.Response {
-	// 优先处理容器输出的内容，即使有 err 也要尝试解析 output
-	output = strings.TrimSpace(output)
-	if output != "" {
-		// 尝试提取JSON部分，如果输出包含换行符，取第一行
-		lines := strings.Split(output, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			// 检查是否是JSON格式
-			if strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}") {
-				response := spec.Decode(line, defaultResponse)
-				if response != nil {
-					return response
-				}
-			}
-		}
+// withResponseMarker prints the marker and then replaces the shell with the command, so the exit code of the
+// command is the exit code of the shell
+func withResponseMarker(command string) string {
+	return fmt.Sprintf("echo %s; exec %s", ResponseMarker, command)
+}
 
-		// 如果所有行都不是有效的JSON，尝试解析整个输出
-		response := spec.Decode(output, defaultResponse)
-		if response != nil {
-			return response
+// ConvertExecResultToResponse converts the result of the command created by CommonFunc to the response.
+// The stdout after the ResponseMarker line must be the response printed by chaosblade, the whole stderr is decoded
+// if the command fails without the response in the stdout. The command which exits with the non-zero code fails
+// even if the response is successful
+func ConvertExecResultToResponse(result container.ExecResult, err error) *spec.Response {
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer", err)
+	}
+	stdout, ok := cutResponseMarker(result.Stdout)
+	if !ok {
+		if result.ExitCode != 0 {
+			return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer", result.Err())
 		}
+		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer",
+			fmt.Sprintf("the command is not executed, the response marker is not found in the output: %s", result.Output()))
 	}
+	return decodeExecResult(result, stdout)
+}
 
-	// 如果 output 为空或解析失败，且有 err，则处理 err
+// ConvertContainerOutputToResponse converts the output of the chaosblade command to the response, the defaultResponse
+// is returned if there is neither the response nor the error.
+//
+// Deprecated: the failure of the command can not be told by the output, use ConvertExecResultToResponse instead
+func ConvertContainerOutputToResponse(output string, err error, defaultResponse *spec.Response) *spec.Response {
+	if stdout, ok := cutResponseMarker(output); ok {
+		output = stdout
+	}
+	if response, ok := parseResponse(output); ok {
+		return response
+	}
 	if err != nil {
-		response := spec.Decode(err.Error(), defaultResponse)
-		if response != nil && response.Success {
+		if response, ok := parseResponse(err.Error()); ok && response.Success {
 			return response
 		}
 		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer", err)
 	}
+	if defaultResponse != nil {
+		return defaultResponse
+	}
+	return decodeExecResult(container.ExecResult{Stdout: output}, output)
+}
 
-	// 如果 output 为空且没有 err，返回通用错误
-	return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer",
-		"cannot get result message from container, please execute recovery and try again")
+// cutResponseMarker returns the output after the last ResponseMarker line, the bool is false if there is no marker
+func cutResponseMarker(output string) (string, bool) {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == ResponseMarker {
+			return strings.Join(lines[i+1:], "\n"), true
+		}
+	}
+	return "", false
 }
 
-// GetContainer return container by container flag, such as container id or container name.
-func GetContainer(ctx context.Context, client container.Container, uid string, containerId, containerName string, containerLabelSelector map[string]string) (container.ContainerInfo, *spec.Response) {
-	if containerId == "" && containerName == "" && len(containerLabelSelector) == 0 {
-		tips := fmt.Sprintf("%s or %s or %s", ContainerIdFlag.Name, ContainerNameFlag.Name, ContainerLabelSelectorFlag.Name)
-		log.Errorf(ctx, "%s", spec.ParameterLess.Sprintf(tips))
-		return container.ContainerInfo{}, spec.ResponseFailWithFlags(spec.ParameterLess, tips)
+// decodeExecResult decodes the response from the stdout which is printed by chaosblade or chaos_os only
+func decodeExecResult(result container.ExecResult, stdout string) *spec.Response {
+	response, ok := parseResponse(stdout)
+	if !ok && result.ExitCode != 0 {
+		response, ok = parseResponse(result.Stderr)
 	}
-	var container container.ContainerInfo
-	var code int32
-	var err error
-	if containerId != "" {
-		container, err, code = client.GetContainerById(ctx, containerId)
-	} else if containerName != "" {
-		container, err, code = client.GetContainerByName(ctx, containerName)
-	} else {
-		container, err, code = client.GetContainerByLabelSelector(containerLabelSelector)
+	if !ok {
+		if result.ExitCode != 0 {
+			return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer", result.Err())
+		}
+		if output := strings.TrimSpace(stdout); output != "" {
+			return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer",
+				fmt.Sprintf("the output is not a response: %s", output))
+		}
+		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer",
+			"cannot get result message from container, please execute recovery and try again")
+	}
+	if result.ExitCode != 0 && response.Success {
+		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "execContainer", result.Err())
+	}
+	return response
+}
+
+// parseResponse decodes the output as a json object with the code and the success fields
+func parseResponse(output string) (*spec.Response, bool) {
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "{") || !strings.HasSuffix(output, "}") {
+		return nil, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(output), &fields); err != nil {
+		return nil, false
+	}
+	if _, ok := fields["code"]; !ok {
+		return nil, false
+	}
+	if _, ok := fields["success"]; !ok {
+		return nil, false
 	}
+	var response spec.Response
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+// GetTargetContainers returns the target containers of the experiment. It fails if the container flags match
+// multiple containers unless the container-multi-target flag is specified or the containers are sampled by
+// the container-count or container-percent flag
+func GetTargetContainers(ctx context.Context, client container.Container, uid string, model *spec.ExpModel) ([]container.ContainerInfo, *spec.Response) {
+	return getTargetContainers(ctx, client, uid, model, false)
+}
+
+// getTargetContainers returns the target containers of the experiment, the state of the containers is checked
+// by selectRunningContainers before the ambiguity check and the sampling if checkState is true
+func getTargetContainers(ctx context.Context, client container.Container, uid string, model *spec.ExpModel,
+	checkState bool,
+) ([]container.ContainerInfo, *spec.Response) {
+	filter, response := GetContainerFilter(ctx, model)
+	if !response.Success {
+		return nil, response
+	}
+	containers, response := GetContainers(ctx, client, uid, filter)
+	if !response.Success {
+		return nil, response
+	}
+	if checkState {
+		allowPaused := model.ActionFlags[AllowPausedContainerFlag.Name] == "true"
+		if containers, response = selectRunningContainers(ctx, filter, containers, allowPaused); response != nil {
+			return nil, response
+		}
+	}
+	if isSampling(model) {
+		// the containers are sampled explicitly, so multiple targets are expected
+		if containers, response = sampleContainers(ctx, model, containers); response != nil {
+			return nil, response
+		}
+	} else if model.ActionFlags[ContainerMultiTargetFlag.Name] != "true" {
+		if response := checkAmbiguousContainers(ctx, filter, containers); response != nil {
+			return nil, response
+		}
+	}
+	if response := enforcePolicy(ctx, uid, containers); response != nil {
+		return nil, response
+	}
+	return containers, spec.ReturnSuccess(containers)
+}
+
+// GetContainers returns all the containers matched the filter, it fails if no container is matched
+func GetContainers(ctx context.Context, client container.Container, uid string, filter container.ContainerFilter) ([]container.ContainerInfo, *spec.Response) {
+	if filter.IsEmpty() {
+		tips := fmt.Sprintf("%s or %s or %s or %s or %s or %s", ContainerIdFlag.Name, ContainerNameFlag.Name,
+			ContainerLabelSelectorFlag.Name, ContainerImageFlag.Name, ContainerNamePatternFlag.Name, PodNameFlag.Name)
+		log.Errorf(ctx, "%s", spec.ParameterLess.Sprintf(tips))
+		return nil, spec.ResponseFailWithFlags(spec.ParameterLess, tips)
+	}
+	containers, err, code := client.GetContainers(ctx, filter)
 	if err != nil {
 		log.Errorf(ctx, "%s", err.Error())
-		return container, spec.ResponseFail(code, err.Error(), nil)
+		return nil, spec.ResponseFail(code, err.Error(), nil)
+	}
+	if len(containers) == 0 {
+		flag, _ := filterFlag(filter)
+		codeType := spec.ParameterInvalidDockContainerId
+		if flag == ContainerNameFlag.Name {
+			codeType = spec.ParameterInvalidDockContainerName
+		}
+		log.Errorf(ctx, "%s", codeType.Sprintf(flag))
+		return nil, spec.ResponseFailWithFlags(codeType, flag)
 	}
-	return container, spec.ReturnSuccess(container)
+	return containers, spec.ReturnSuccess(containers)
 }
 
-func parseContainerLabelSelector(raw string) map[string]string {
-	labels := make(map[string]string, 0)
-
-	if raw != "" {
-		for _, label := range strings.Split(raw, ",") {
-			keyAndValue := strings.Split(label, "=")
-			if len(keyAndValue) == 2 {
-				labels[keyAndValue[0]] = keyAndValue[1]
+// GetContainerFilter returns the container filter by the container flags, container-id is preferred,
+// and then container-name and container-label-selector. The container-image, container-name-pattern and the pod flags
+// are matched in addition to them, and the containers matched the exclusion flags are excluded
+func GetContainerFilter(ctx context.Context, model *spec.ExpModel) (container.ContainerFilter, *spec.Response) {
+	flags := model.ActionFlags
+	selector, err := container.ParseLabelSelector(flags[ContainerLabelSelectorFlag.Name])
+	if err != nil {
+		log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerLabelSelectorFlag.Name, flags[ContainerLabelSelectorFlag.Name], err))
+		return container.ContainerFilter{}, spec.ResponseFailWithFlags(spec.ParameterInvalid,
+			ContainerLabelSelectorFlag.Name, flags[ContainerLabelSelectorFlag.Name], err)
+	}
+	filter := newContainerFilter(flags[ContainerIdFlag.Name], flags[ContainerNameFlag.Name], selector)
+	if image := flags[ContainerImageFlag.Name]; image != "" {
+		if container.IsImageRegexp(image) {
+			if err := container.ValidateImagePattern(image); err != nil {
+				log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerImageFlag.Name, image, err))
+				return container.ContainerFilter{}, spec.ResponseFailWithFlags(spec.ParameterInvalid, ContainerImageFlag.Name, image, err)
+			}
+		}
+		// the image works together with the other container flags
+		filter.Image = image
+	}
+	if pattern := flags[ContainerNamePatternFlag.Name]; pattern != "" {
+		if err := container.ValidateNamePattern(pattern); err != nil {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerNamePatternFlag.Name, pattern, err))
+			return container.ContainerFilter{}, spec.ResponseFailWithFlags(spec.ParameterInvalid, ContainerNamePatternFlag.Name, pattern, err)
+		}
+		filter.NamePattern = pattern
+	}
+	for flag, label := range podLabelFlags() {
+		if value := flags[flag]; value != "" {
+			if filter.Labels == nil {
+				filter.Labels = make(map[string]string)
+			}
+			filter.Labels[label] = value
+		}
+	}
+	// the pause containers can be only targeted by the container id
+	filter.IncludeSandbox = filter.ContainerId != ""
+	if excludeLabel := flags[ExcludeContainerLabelFlag.Name]; excludeLabel != "" {
+		excludeSelector, err := container.ParseLabelSelector(excludeLabel)
+		if err != nil {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ExcludeContainerLabelFlag.Name, excludeLabel, err))
+			return container.ContainerFilter{}, spec.ResponseFailWithFlags(spec.ParameterInvalid, ExcludeContainerLabelFlag.Name, excludeLabel, err)
+		}
+		filter.ExcludeLabelSelector = excludeSelector
+	}
+	if excludeName := flags[ExcludeContainerNameFlag.Name]; excludeName != "" {
+		for _, name := range container.ParseNamePatterns(excludeName) {
+			if err := container.ValidateNamePattern(name); err != nil {
+				log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ExcludeContainerNameFlag.Name, excludeName, err))
+				return container.ContainerFilter{}, spec.ResponseFailWithFlags(spec.ParameterInvalid, ExcludeContainerNameFlag.Name, excludeName, err)
 			}
+			filter.ExcludeNames = append(filter.ExcludeNames, name)
 		}
 	}
+	return filter, spec.ReturnSuccess(nil)
+}
+
+func newContainerFilter(containerId, containerName string, selector container.LabelSelector) container.ContainerFilter {
+	if containerId != "" {
+		return container.ContainerFilter{ContainerId: containerId}
+	}
+	if containerName != "" {
+		return container.ContainerFilter{ContainerName: containerName}
+	}
+	return container.ContainerFilter{
+		Labels:        selector.EqualityLabels(),
+		LabelSelector: selector,
+	}
+}
+
+// filterFlag returns the flag name and the value of the filter
+func filterFlag(filter container.ContainerFilter) (string, string) {
+	if filter.ContainerId != "" {
+		return ContainerIdFlag.Name, filter.ContainerId
+	}
+	if filter.ContainerName != "" {
+		return ContainerNameFlag.Name, filter.ContainerName
+	}
+	if len(filter.LabelSelector) > 0 {
+		return ContainerLabelSelectorFlag.Name, filter.LabelSelector.String()
+	}
+	if len(filter.Labels) == 0 && filter.Image != "" {
+		return ContainerImageFlag.Name, filter.Image
+	}
+	if len(filter.Labels) == 0 && filter.NamePattern != "" {
+		return ContainerNamePatternFlag.Name, filter.NamePattern
+	}
+	labels := make([]string, 0, len(filter.Labels))
+	for k, v := range filter.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(labels)
+	return ContainerLabelSelectorFlag.Name, strings.Join(labels, ",")
+}
 
-	return labels
+// selectRunningContainers checks the state of the containers when creating. The containers named explicitly by
+// the container id or the container name are refused if they are not running, the other containers which are not
+// running are dropped, such as the exited containers kept by the kubelet after the restarts
+func selectRunningContainers(ctx context.Context, filter container.ContainerFilter, containers []container.ContainerInfo,
+	allowPaused bool,
+) ([]container.ContainerInfo, *spec.Response) {
+	if _, ok := spec.IsDestroy(ctx); ok {
+		return containers, nil
+	}
+	if filter.ContainerId != "" || filter.ContainerName != "" {
+		if response := checkContainerState(ctx, containers, allowPaused); response != nil {
+			return nil, response
+		}
+		return containers, nil
+	}
+	running := make([]container.ContainerInfo, 0, len(containers))
+	for _, ctr := range containers {
+		if containerStateTips(ctr.State, allowPaused) == "" {
+			running = append(running, ctr)
+		}
+	}
+	if len(running) == 0 {
+		// all the matched containers are refused, report the first one
+		return nil, checkContainerState(ctx, containers, allowPaused)
+	}
+	if len(running) < len(containers) {
+		log.Infof(ctx, "%d of %d matched containers are not running, they are skipped", len(containers)-len(running), len(containers))
+	}
+	return running, nil
+}
+
+// checkContainerState refuses the containers which are not running when creating, the paused containers are
+// allowed only if allowPaused is true. The containers whose state is unknown are not refused
+func checkContainerState(ctx context.Context, containers []container.ContainerInfo, allowPaused bool) *spec.Response {
+	if _, ok := spec.IsDestroy(ctx); ok {
+		return nil
+	}
+	for _, ctr := range containers {
+		tips := containerStateTips(ctr.State, allowPaused)
+		if tips == "" {
+			continue
+		}
+		log.Errorf(ctx, "%s", ContainerNotRunning.Sprintf(ctr.ContainerId, ctr.State, tips))
+		return spec.ResponseFailWithFlags(ContainerNotRunning, ctr.ContainerId, ctr.State, tips)
+	}
+	return nil
+}
+
+// containerStateTips returns the reason why the container in the state can not be injected, empty if it can
+func containerStateTips(state string, allowPaused bool) string {
+	switch state {
+	case container.StateRunning, container.StateUnknown, "":
+		return ""
+	case container.StatePaused:
+		if allowPaused {
+			return ""
+		}
+		return fmt.Sprintf("please unpause it or specify the %s flag", AllowPausedContainerFlag.Name)
+	default:
+		return "only the running containers can be injected"
+	}
+}
+
+// checkAmbiguousContainers returns the failed response if multiple containers are matched
+func checkAmbiguousContainers(ctx context.Context, filter container.ContainerFilter, containers []container.ContainerInfo) *spec.Response {
+	if len(containers) <= 1 {
+		return nil
+	}
+	ids := make([]string, 0, len(containers))
+	namespaces := make(map[string][]string)
+	for _, ctr := range containers {
+		ids = append(ids, ctr.ContainerId)
+		namespaces[ctr.ContainerId] = append(namespaces[ctr.ContainerId], ctr.Namespace)
+	}
+	flag, value := filterFlag(filter)
+	reason := fmt.Sprintf("%d containers are matched: %s, please specify the %s flag to target all of them",
+		len(containers), strings.Join(ids, ", "), ContainerMultiTargetFlag.Name)
+	for _, ctr := range containers {
+		if found := namespaces[ctr.ContainerId]; len(found) > 1 {
+			// the same container id exists in several containerd namespaces
+			reason = fmt.Sprintf("the container %s is found in the containerd namespaces %s, please specify the %s flag",
+				ctr.ContainerId, strings.Join(found, ", "), ContainerNamespace.Name)
+			break
+		}
+	}
+	log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(flag, value, reason))
+	return spec.ResponseFailWithFlags(spec.ParameterInvalid, flag, value, reason)
+}
+
+// podLabelFlags returns the pod flags and the kubernetes labels which they are resolved by
+func podLabelFlags() map[string]string {
+	return map[string]string{
+		PodNamespaceFlag.Name:     container.KubernetesPodNamespaceLabel,
+		PodNameFlag.Name:          container.KubernetesPodNameLabel,
+		K8sContainerNameFlag.Name: container.KubernetesContainerNameLabel,
+	}
+}
+
+// isPodTargeting returns true if any of the pod flags is specified
+func isPodTargeting(expModel *spec.ExpModel) bool {
+	for flag := range podLabelFlags() {
+		if expModel.ActionFlags[flag] != "" {
+			return true
+		}
+	}
+	return false
 }
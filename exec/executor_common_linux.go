@@ -35,6 +35,7 @@
 package exec
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -48,92 +49,98 @@ import (
 	"time"
 
 	osexec "github.com/chaosblade-io/chaosblade-exec-os/exec"
-	"github.com/chaosblade-io/chaosblade-exec-os/exec/model"
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
 
 	"github.com/containerd/cgroups"
 	cgroupsv2 "github.com/containerd/cgroups/v2"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
-// CommonExecutor is an executor implementation which used copy chaosblade tool to the target container and executed
+// CommonExecutor is an executor implementation which executes chaos_os in the namespaces of the container process
 type CommonExecutor struct {
 	BaseClientExecutor
+	// Namespaces are declared by the action, they are overridden by the ns flag
+	Namespaces []string
 }
 
-func NewCommonExecutor() *CommonExecutor {
+// NewCommonExecutor returns the executor which executes the action in the namespaces,
+// the DefaultNamespaces are used if no namespace is declared
+func NewCommonExecutor(namespaces ...string) *CommonExecutor {
+	if len(namespaces) == 0 {
+		namespaces = DefaultNamespaces
+	}
 	return &CommonExecutor{
-		BaseClientExecutor{
+		BaseClientExecutor: BaseClientExecutor{
 			CommandFunc: CommonFunc,
+			Kind:        ExecutorKindNSExec,
 		},
+		Namespaces: namespaces,
 	}
 }
 
+// NewNetworkExecutor returns the executor which executes the action in the namespaces declared by the network actions
+func NewNetworkExecutor() *CommonExecutor {
+	return NewCommonExecutor(NetworkNamespaces...)
+}
+
 func (r *CommonExecutor) Name() string {
 	return "CommonExecutor"
 }
 
-func (r *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
-	if err := r.SetClient(expModel); err != nil {
+func (r *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) (response *spec.Response) {
+	client, err := r.PrepareClient(ctx, uid, expModel)
+	if err != nil {
 		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("GetClient,error: %v", err))
 		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "GetClient", err)
 	}
-	containerId := expModel.ActionFlags[ContainerIdFlag.Name]
-	containerName := expModel.ActionFlags[ContainerNameFlag.Name]
-	containerLabelSelector := parseContainerLabelSelector(expModel.ActionFlags[ContainerLabelSelectorFlag.Name])
-	container, response := GetContainer(ctx, r.Client, uid, containerId, containerName, containerLabelSelector)
+	defer func() { response = client.WithDetection(response) }()
+	namespaces := r.Namespaces
+	if value := expModel.ActionFlags[NsFlag.Name]; value != "" {
+		if namespaces, err = ParseNamespaces(value); err != nil {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(NsFlag.Name, value, err))
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, NsFlag.Name, value, err)
+		}
+	}
+	containers, response := r.GetExperimentContainers(ctx, client, uid, expModel)
 	if !response.Success {
 		return response
 	}
-	pid, err, code := r.Client.GetPidById(ctx, container.ContainerId)
-	if err != nil {
-		log.Errorf(ctx, "GetPidById,error: %v", err)
-		return spec.ResponseFail(code, err.Error(), nil)
-	}
-
-	var args string
-	var flags string
-
-	nsFlags := GetNSExecFlags()
-	m := make(map[string]string, len(nsFlags))
-	for _, f := range nsFlags {
-		m[f.FlagName()] = f.FlagName()
-	}
 
 	cgroupRoot := os.Getenv("CGROUP_ROOT")
 	if cgroupRoot != "" && expModel.ActionProcessHang {
 		expModel.ActionFlags["cgroup-root"] = cgroupRoot
 	}
 
-	for k, v := range expModel.ActionFlags {
-		if v == "" || m[k] != "" || k == "timeout" {
-			continue
-		}
-		flags = fmt.Sprintf("%s --%s=%s", flags, k, v)
-	}
-	_, isDestroy := spec.IsDestroy(ctx)
+	return r.FanOut(ctx, client, uid, expModel, containers, func(ctx context.Context, containerInfo container.ContainerInfo) *spec.Response {
+		return r.execInContainer(uid, ctx, client, expModel, namespaces, containerInfo)
+	})
+}
 
-	if isDestroy {
-		args = fmt.Sprintf("%s %s %s%s --uid=%s", spec.Destroy, expModel.Target, expModel.ActionName, flags, uid)
-	} else {
-		args = fmt.Sprintf("%s %s %s%s --uid=%s", spec.Create, expModel.Target, expModel.ActionName, flags, uid)
+// execInContainer executes the experiment in the namespaces of the container
+func (r *CommonExecutor) execInContainer(uid string, ctx context.Context, client container.Container, expModel *spec.ExpModel,
+	namespaces []string, containerInfo container.ContainerInfo,
+) *spec.Response {
+	if r.execByCopy(ctx, uid, client, containerInfo) {
+		recordContainerExecutor(ctx, ExecutorKindCopy)
+		return r.execByAPI(uid, ctx, client, expModel, containerInfo)
+	}
+	recordContainerExecutor(ctx, ExecutorKindNSExec)
+	pid, err, code := client.GetPidById(ctx, containerInfo.ContainerId)
+	if err != nil {
+		log.Errorf(ctx, "GetPidById,error: %v", err)
+		return spec.ResponseFail(code, err.Error(), nil)
 	}
 
-	args = fmt.Sprintf("%s %s %s %s %s",
-		args,
-		fmt.Sprintf("--%s=%s", model.ChannelFlag.Name, spec.NSExecBin),
-		fmt.Sprintf("--%s=%d", model.NsTargetFlag.Name, pid),
-		fmt.Sprintf("--%s=%s", model.NsPidFlag.Name, spec.True),
-		fmt.Sprintf("--%s=%s", model.NsMntFlag.Name, spec.True),
-	)
+	args := chaosOsArgs(ctx, uid, expModel, pid, namespaces)
 
-	if !isDestroy && expModel.ActionProcessHang {
-		return execForHangAction(uid, ctx, expModel, pid, args)
+	if _, isDestroy := spec.IsDestroy(ctx); !isDestroy && expModel.ActionProcessHang {
+		return execForHangAction(uid, ctx, expModel, pid, namespaces, args)
 	}
 
 	chaosOsBin := path.Join(util.GetProgramPath(), spec.BinPath, spec.ChaosOsBin)
-	argsArray := strings.Split(args, " ")
 
 	log.Debugf(ctx, "chaosOsBin full path: %s", chaosOsBin)
 
@@ -142,14 +149,62 @@ func (r *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.Ex
 		log.Debugf(ctx, "chaos_os binary not found at: %s", chaosOsBin)
 	}
 
-	command := exec.CommandContext(ctx, chaosOsBin, argsArray...)
-	output, err := command.CombinedOutput()
-	outMsg := string(output)
-	log.Debugf(ctx, "Command Result, output: %v, err: %v", outMsg, err)
+	bin, argv := withNamespaces(pid, namespaces, chaosOsBin, args)
+	recordContainerExec(ctx, pid, append([]string{bin}, argv...))
+	command := exec.CommandContext(ctx, bin, argv...)
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	start := time.Now()
+	err = command.Run()
+	result := container.ExecResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	log.Debugf(ctx, "Command Result, output: %v, errMsg: %v, err: %v", result.Stdout, result.Stderr, err)
 	if err != nil {
-		return spec.ReturnFail(spec.OsCmdExecFailed, fmt.Sprintf("command exec failed, %s", err.Error()))
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return spec.ReturnFail(spec.OsCmdExecFailed, fmt.Sprintf("command exec failed, %s", err.Error()))
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	return decodeExecResult(result, result.Stdout)
+}
+
+// execByCopy returns true if the experiment is executed in the container by the copy executor instead of nsexec, such
+// as for the remote runtimes and the sandboxed containers whose processes are not on the local host. When destroying,
+// the executor recorded for the container at creation is used, so the experiment is reverted by the same path
+func (r *CommonExecutor) execByCopy(ctx context.Context, uid string, client container.Container, containerInfo container.ContainerInfo) bool {
+	if _, isDestroy := spec.IsDestroy(ctx); isDestroy {
+		if kind := recordedContainerExecutor(uid, containerInfo.ContainerId, containerInfo.Namespace); kind != "" {
+			return kind == ExecutorKindCopy
+		}
+	}
+	checker, ok := client.(container.NSExecChecker)
+	if !ok {
+		return false
+	}
+	if err := checker.CheckNSExec(ctx, containerInfo.ContainerId); err != nil {
+		log.Infof(ctx, "nsexec is not viable for the container %s, execute the experiment by the exec api, %v",
+			containerInfo.ContainerId, err)
+		return true
+	}
+	return false
+}
+
+// execByAPI deploys the chaosblade tool to the container and executes the experiment by the exec api of the runtime
+func (r *CommonExecutor) execByAPI(uid string, ctx context.Context, client container.Container, expModel *spec.ExpModel,
+	containerInfo container.ContainerInfo,
+) *spec.Response {
+	executor := &RunCmdInContainerExecutorByCP{
+		BaseClientExecutor: BaseClientExecutor{
+			CommandFunc: r.CommandFunc,
+			Kind:        ExecutorKindCopy,
+		},
 	}
-	return spec.Decode(outMsg, nil)
+	return executor.execInContainer(uid, ctx, client, expModel, containerInfo)
 }
 
 func (r *CommonExecutor) SetChannel(channel spec.Channel) {
@@ -161,16 +216,15 @@ func (r *CommonExecutor) DeployChaosBlade(ctx context.Context, containerId strin
 	return nil
 }
 
-func execForHangAction(uid string, ctx context.Context, expModel *spec.ExpModel, pid int32, args string) *spec.Response {
+func execForHangAction(uid string, ctx context.Context, expModel *spec.ExpModel, pid int32, namespaces []string, args []string) *spec.Response {
 	chaosOsBin := path.Join(util.GetProgramPath(), spec.BinPath, spec.ChaosOsBin)
 
-	args = fmt.Sprintf("-s -t %d -p -n -- %s %s", pid, chaosOsBin, args)
-
-	argsArray := strings.Split(args, " ")
+	nsexecArgs := NewArgs("-s", "-t", strconv.Itoa(int(pid))).Add(hangNamespaceArgs(pid, namespaces)...)
+	argsArray := nsexecArgs.Add("--", chaosOsBin).Add(args...).Argv()
 
 	bin := path.Join(util.GetProgramPath(), spec.BinPath, spec.NSExecBin)
-	log.Debugf(ctx, "run command, %s %s", bin, args)
-
+	log.Debugf(ctx, "run command, %s %q", bin, argsArray)
+	recordContainerExec(ctx, pid, append([]string{bin}, argsArray...))
 	command := exec.CommandContext(ctx, bin, argsArray...)
 	command.SysProcAttr = &syscall.SysProcAttr{}
 
@@ -355,6 +409,7 @@ func execForHangAction(uid string, ctx context.Context, expModel *spec.ExpModel,
 		}
 	}
 
+	recordHangProcess(ctx, command.Process.Pid)
 	return spec.ReturnSuccess(command.Process.Pid)
 }
 
@@ -423,3 +478,27 @@ func getProcessCmdline(pid int) (string, error) {
 
 	return string(b), nil
 }
+
+// isExperimentProcessAlive returns true if the process of the experiment, such as the hang process, is still running.
+// The command line must contain the uid in case that the pid is reused
+func isExperimentProcessAlive(pid int, uid string) bool {
+	if pid <= 0 {
+		return false
+	}
+	cmdline, err := getProcessCmdline(pid)
+	if err != nil || cmdline == "" {
+		return false
+	}
+	return strings.Contains(cmdline, uid)
+}
+
+// killExperimentProcess kills the process of the experiment, the bool is false if it is not running
+func killExperimentProcess(pid int, uid string) (bool, error) {
+	if !isExperimentProcessAlive(pid, uid) {
+		return false, nil
+	}
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return false, err
+	}
+	return true, nil
+}
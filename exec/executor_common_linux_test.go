@@ -0,0 +1,140 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeExecClient is the runtime client whose containers can not be entered by nsexec, such as the sandboxed ones,
+// the commands are executed by the exec api and recorded
+type fakeExecClient struct {
+	fakeContainer
+	nsexecErr error
+	mu        sync.Mutex
+	commands  []string
+	pidQuery  bool
+}
+
+func (f *fakeExecClient) CheckNSExec(ctx context.Context, containerId string) error {
+	return f.nsexecErr
+}
+
+func (f *fakeExecClient) GetPidById(ctx context.Context, containerId string) (int32, error, int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pidQuery = true
+	return 0, errors.New("the container is not on the local host"), spec.ContainerExecFailed.Code
+}
+
+func (f *fakeExecClient) ExecContainer(ctx context.Context, containerId, command string) (container.ExecResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.commands = append(f.commands, command)
+	if strings.Contains(command, "echo True") {
+		// the chaosblade tool is deployed already
+		return container.ExecResult{Stdout: "True\n"}, nil
+	}
+	return container.ExecResult{Stdout: ResponseMarker + "\n" + `{"code":200,"success":true,"result":"ok"}`}, nil
+}
+
+// writeReleaseFile writes the chaosblade release whose top directory is chaosblade-test
+func writeReleaseFile(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "chaosblade-test.tar.gz")
+	out, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	writer := tar.NewWriter(out)
+	if err := writer.WriteHeader(&tar.Header{Name: "chaosblade-test/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestCommonExecutorFallsBackToCopy(t *testing.T) {
+	useTempStateDir(t)
+	uid := "uid-fallback"
+	client := &fakeExecClient{
+		fakeContainer: fakeContainer{containers: []container.ContainerInfo{{ContainerId: "sandboxed", Namespace: "k8s.io"}}},
+		nsexecErr:     errors.New("the container is sandboxed"),
+	}
+	executor := NewCommonExecutor(DefaultNamespaces...)
+	expModel := &spec.ExpModel{
+		Target:     "cpu",
+		ActionName: "fullload",
+		ActionFlags: map[string]string{
+			ContainerIdFlag.Name:       "sandboxed",
+			ChaosBladeReleaseFlag.Name: writeReleaseFile(t),
+		},
+	}
+	exec := func(ctx context.Context) *spec.Response {
+		experimentClient := &ExperimentClient{Container: client}
+		containers, response := executor.GetExperimentContainers(ctx, client, uid, expModel)
+		if !response.Success {
+			return response
+		}
+		return executor.FanOut(ctx, experimentClient, uid, expModel, containers, func(ctx context.Context, containerInfo container.ContainerInfo) *spec.Response {
+			return executor.execInContainer(uid, ctx, client, expModel, executor.Namespaces, containerInfo)
+		})
+	}
+
+	if response := exec(context.Background()); !response.Success {
+		t.Fatalf("the experiment is not executed by the copy executor, %+v", response)
+	}
+	state, ok, err := LoadExperimentState(uid)
+	if err != nil || !ok {
+		t.Fatalf("LoadExperimentState() = %v, %v", ok, err)
+	}
+	if len(state.Containers) != 1 || state.Containers[0].Executor != ExecutorKindCopy {
+		t.Fatalf("the copy executor is not recorded, got %+v", state.Containers)
+	}
+
+	// the experiment is reverted by the recorded executor, even if nsexec is viable now
+	client.nsexecErr = nil
+	client.commands = nil
+	if response := exec(spec.SetDestroyFlag(context.Background(), uid)); !response.Success {
+		t.Fatalf("the experiment is not reverted by the copy executor, %+v", response)
+	}
+	if client.pidQuery {
+		t.Errorf("the experiment is reverted by nsexec instead of the recorded copy executor")
+	}
+	if len(client.commands) != 1 || !strings.Contains(client.commands[0], spec.Destroy) {
+		t.Errorf("the experiment is not destroyed by the exec api, got %q", client.commands)
+	}
+	if _, ok, _ := LoadExperimentState(uid); ok {
+		t.Errorf("the state is not removed after reverting")
+	}
+}
@@ -35,6 +35,9 @@
 package exec
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
@@ -44,3 +47,23 @@ import (
 func GetClientByRuntime(expModel *spec.ExpModel) (container.Container, error) {
 	return docker.NewClient(expModel.ActionFlags[EndpointFlag.Name])
 }
+
+func getClient(ctx context.Context, expModel *spec.ExpModel) (container.Container, *RuntimeDetection, error) {
+	cli, err := GetClientByRuntime(expModel)
+	return cli, nil, err
+}
+
+// isExperimentProcessAlive returns false, the processes of the experiments are only started on linux
+func isExperimentProcessAlive(pid int, uid string) bool {
+	return false
+}
+
+// killExperimentProcess does nothing, the processes of the experiments are only started on linux
+func killExperimentProcess(pid int, uid string) (bool, error) {
+	return false, nil
+}
+
+// startRevertWatcher is not supported on darwin
+func startRevertWatcher(uid, file string) (int, error) {
+	return 0, fmt.Errorf("the revert watcher is not supported on darwin")
+}
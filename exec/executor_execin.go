@@ -27,6 +27,7 @@ import (
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 	"github.com/chaosblade-io/chaosblade-exec-cri/version"
 )
 
@@ -47,6 +48,7 @@ func NewRunCmdInContainerExecutorByCP() RunCmdInContainerExecutor {
 	return &RunCmdInContainerExecutorByCP{
 		BaseClientExecutor{
 			CommandFunc: CommonFunc,
+			Kind:        ExecutorKindCopy,
 		},
 	}
 }
@@ -55,18 +57,26 @@ func (r *RunCmdInContainerExecutorByCP) Name() string {
 	return "runCmdInContainerExecutorByCP"
 }
 
-func (r *RunCmdInContainerExecutorByCP) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
-	if err := r.SetClient(expModel); err != nil {
+func (r *RunCmdInContainerExecutorByCP) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) (response *spec.Response) {
+	client, err := r.PrepareClient(ctx, uid, expModel)
+	if err != nil {
 		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("GetClient", err))
 		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "GetClient", err)
 	}
-	containerId := expModel.ActionFlags[ContainerIdFlag.Name]
-	containerName := expModel.ActionFlags[ContainerNameFlag.Name]
-	containerLabelSelector := parseContainerLabelSelector(expModel.ActionFlags[ContainerLabelSelectorFlag.Name])
-	container, response := GetContainer(ctx, r.Client, uid, containerId, containerName, containerLabelSelector)
+	defer func() { response = client.WithDetection(response) }()
+	containers, response := r.GetExperimentContainers(ctx, client, uid, expModel)
 	if !response.Success {
 		return response
 	}
+	return r.FanOut(ctx, client, uid, expModel, containers, func(ctx context.Context, containerInfo container.ContainerInfo) *spec.Response {
+		return r.execInContainer(uid, ctx, client, expModel, containerInfo)
+	})
+}
+
+// execInContainer deploys the chaosblade tool to the container and executes the command in it
+func (r *RunCmdInContainerExecutorByCP) execInContainer(uid string, ctx context.Context, client container.Container, expModel *spec.ExpModel,
+	containerInfo container.ContainerInfo,
+) *spec.Response {
 	command := r.CommandFunc(uid, ctx, expModel)
 	if _, ok := spec.IsDestroy(ctx); !ok {
 		// Create
@@ -100,15 +110,15 @@ func (r *RunCmdInContainerExecutorByCP) Exec(uid string, ctx context.Context, ex
 			return spec.ResponseFailWithFlags(spec.ParameterInvalid, ChaosBladeReleaseFlag.Name, chaosbladeReleaseFile, "the obtained directory name is empty")
 
 		}
-		err = r.DeployChaosBlade(ctx, container.ContainerId, chaosbladeReleaseFile, extractedDirName, override)
+		err = deployChaosBlade(ctx, client, containerInfo.ContainerId, chaosbladeReleaseFile, extractedDirName, override)
 		if err != nil {
 			log.Errorf(ctx, "DeployChaosBlade err: %v", err)
 			return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "DeployChaosBlade", err)
 		}
 	}
-	output, err := r.Client.ExecContainer(ctx, container.ContainerId, command)
-	var defaultResponse *spec.Response
-	return ConvertContainerOutputToResponse(output, err, defaultResponse)
+	recordContainerExec(ctx, containerInfo.Pid, []string{"/bin/sh", "-c", command})
+	result, err := client.ExecContainer(ctx, containerInfo.ContainerId, command)
+	return ConvertExecResultToResponse(result, err)
 }
 
 func (r *RunCmdInContainerExecutorByCP) SetChannel(channel spec.Channel) {
@@ -116,28 +126,41 @@ func (r *RunCmdInContainerExecutorByCP) SetChannel(channel spec.Channel) {
 
 func (r *RunCmdInContainerExecutorByCP) DeployChaosBlade(ctx context.Context, containerId string,
 	srcFile, extractDirName string, override bool,
+) error {
+	return deployChaosBlade(ctx, r.Client, containerId, srcFile, extractDirName, override)
+}
+
+// deployChaosBlade copies the chaosblade tool to the container by the client
+func deployChaosBlade(ctx context.Context, client container.Container, containerId string,
+	srcFile, extractDirName string, override bool,
 ) error {
 	// check if the blade tool exists
 	// todo for test
-	output, err := r.Client.ExecContainer(ctx, containerId, fmt.Sprintf("[ -e %s ] && echo True || echo False", BladeBin))
-	if err == nil && strings.Contains(output, "True") && !override {
+	result, err := client.ExecContainer(ctx, containerId, fmt.Sprintf("[ -e %s ] && echo True || echo False", container.ShellQuote(BladeBin)))
+	if err == nil && result.ExitCode == 0 && strings.Contains(result.Stdout, "True") && !override {
 		return nil
 	}
 
-	err = r.Client.CopyToContainer(ctx, containerId, srcFile, DstChaosBladeDir, extractDirName, override)
+	err = client.CopyToContainer(ctx, containerId, srcFile, DstChaosBladeDir, extractDirName, override)
 	if err != nil {
 		return err
 	}
 
 	dstBladeDir := path.Join(DstChaosBladeDir, extractDirName)
 	expectBladeDir := path.Join(DstChaosBladeDir, "chaosblade")
-	rmCmd := fmt.Sprintf("rm -rf %s", expectBladeDir)
-	_, err = r.Client.ExecContainer(ctx, containerId, rmCmd)
+	rmCmd := container.ShellJoin("rm", "-rf", expectBladeDir)
+	result, err = client.ExecContainer(ctx, containerId, rmCmd)
 	if err != nil {
 		return err
 	}
+	if err := result.Err(); err != nil {
+		return err
+	}
 
-	renameCmd := fmt.Sprintf("mv %s %s", dstBladeDir, expectBladeDir)
-	_, err = r.Client.ExecContainer(ctx, containerId, renameCmd)
-	return err
+	renameCmd := container.ShellJoin("mv", dstBladeDir, expectBladeDir)
+	result, err = client.ExecContainer(ctx, containerId, renameCmd)
+	if err != nil {
+		return err
+	}
+	return result.Err()
 }
@@ -0,0 +1,377 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// FanOutParallelism is the max number of the containers which are handled at the same time
+var FanOutParallelism = 8
+
+// ContainerResult is the experiment result of one target container
+type ContainerResult struct {
+	ContainerId   string      `json:"containerId"`
+	ContainerName string      `json:"containerName,omitempty"`
+	Success       bool        `json:"success"`
+	Code          int32       `json:"code"`
+	Err           string      `json:"error,omitempty"`
+	Result        interface{} `json:"result,omitempty"`
+	// Reverted is true if the experiment is injected but reverted, because it failed in the other containers
+	Reverted bool `json:"reverted,omitempty"`
+	// Pod is the kubernetes pod of the container
+	Pod *container.PodIdentity `json:"pod,omitempty"`
+}
+
+// ContainerExecFunc executes the experiment in the container
+type ContainerExecFunc func(ctx context.Context, containerInfo container.ContainerInfo) *spec.Response
+
+// resumeWatchersOnce resumes the revert watchers once in the process, such as the first experiment after rebooting
+var resumeWatchersOnce sync.Once
+
+// PrepareClient returns the client of the experiment. When destroying, the runtime, the endpoint and the namespace
+// recorded at creation are used, so the experiment is reverted by the same runtime.
+// The revert watchers which are not running are resumed before the first experiment created by the process, and by
+// the experiment commands. They are not resumed when destroying, otherwise the experiment may be reverted by its
+// watcher at the same time
+func (b *BaseClientExecutor) PrepareClient(ctx context.Context, uid string, expModel *spec.ExpModel) (*ExperimentClient, error) {
+	_, isDestroy := spec.IsDestroy(ctx)
+	if !isDestroy {
+		resumeWatchersOnce.Do(func() {
+			if err := ResumeRevertWatchers(ctx); err != nil {
+				log.Warnf(ctx, "%v", err)
+			}
+		})
+	}
+	if isDestroy {
+		state, ok, err := LoadExperimentState(uid)
+		if err != nil {
+			log.Warnf(ctx, "load the state of %s failed, %v", uid, err)
+		}
+		if ok && state.Runtime != "" {
+			expModel = stateExpModel(state, expModel.ActionFlags)
+		}
+	}
+	cli, detection, err := getClient(ctx, expModel)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentClient{Container: cli, Detection: detection}, nil
+}
+
+// GetExperimentContainers returns the target containers of the experiment. When destroying, the containers recorded
+// at creation are returned, so the experiment is reverted in exactly the injected containers
+func (b *BaseClientExecutor) GetExperimentContainers(ctx context.Context, client container.Container, uid string, expModel *spec.ExpModel) ([]container.ContainerInfo, *spec.Response) {
+	if _, ok := spec.IsDestroy(ctx); !ok {
+		return getTargetContainers(ctx, client, uid, expModel, true)
+	}
+	state, ok, err := LoadExperimentState(uid)
+	if err != nil {
+		log.Warnf(ctx, "load the state of %s failed, %v", uid, err)
+	}
+	if !ok {
+		return GetTargetContainers(ctx, client, uid, unsampledExpModel(expModel))
+	}
+	containers := make([]container.ContainerInfo, 0, len(state.Containers))
+	for _, target := range state.Containers {
+		matched, err, code := client.GetContainers(ctx, container.ContainerFilter{
+			ContainerId:    target.ContainerId,
+			Namespace:      target.Namespace,
+			IncludeSandbox: true,
+		})
+		if err != nil {
+			log.Errorf(ctx, "%s", err.Error())
+			return nil, spec.ResponseFail(code, err.Error(), nil)
+		}
+		if len(matched) == 0 {
+			log.Warnf(ctx, "the target container %s of %s does not exist, skip it", target.ContainerId, uid)
+			continue
+		}
+		containers = append(containers, matched[0])
+	}
+	return containers, spec.ReturnSuccess(containers)
+}
+
+// unsampledExpModel returns the experiment model which targets all the matched containers instead of the sampled
+// ones. The sampled containers are unknown without the state, sampling again may pick the other containers
+func unsampledExpModel(expModel *spec.ExpModel) *spec.ExpModel {
+	if !isSampling(expModel) {
+		return expModel
+	}
+	flags := make(map[string]string, len(expModel.ActionFlags))
+	for k, v := range expModel.ActionFlags {
+		flags[k] = v
+	}
+	delete(flags, ContainerCountFlag.Name)
+	delete(flags, ContainerPercentFlag.Name)
+	flags[ContainerMultiTargetFlag.Name] = "true"
+	model := *expModel
+	model.ActionFlags = flags
+	return &model
+}
+
+// FanOut executes the experiment in every container with bounded parallelism and aggregates the responses.
+// The containers are recorded if all of them are injected, otherwise the injected ones are reverted and reported
+// as reverted. The containers which fail to revert are recorded, so they are reverted by destroying the experiment
+func (b *BaseClientExecutor) FanOut(ctx context.Context, client *ExperimentClient, uid string, expModel *spec.ExpModel, containers []container.ContainerInfo,
+	fn ContainerExecFunc,
+) *spec.Response {
+	ctx = container.WithNSExecChecks(ctx)
+	_, isDestroy := spec.IsDestroy(ctx)
+	var revert *ScheduledRevert
+	if !isDestroy {
+		// the targets reserved by the policy are counted by the state after it is saved
+		defer releaseReservation(ctx, uid)
+		var flag string
+		var err error
+		if revert, flag, err = parseRevert(b.Kind, expModel); err != nil {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(flag, expModel.ActionFlags[flag], err))
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, flag, expModel.ActionFlags[flag], err)
+		}
+	}
+	records, responses := runInContainers(ctx, containers, fn)
+	failed := make([]TargetContainer, 0)
+	succeeded := make([]int, 0, len(containers))
+	for idx, response := range responses {
+		if response.Success {
+			succeeded = append(succeeded, idx)
+		} else {
+			failed = append(failed, records[idx])
+		}
+	}
+
+	if isDestroy {
+		// keep the failed containers, so they can be reverted by destroying again
+		state := b.saveState(ctx, client, uid, expModel, failed)
+		if len(failed) == 0 {
+			cancelRevert(ctx, uid, state.Revert)
+		}
+	} else if len(failed) == 0 {
+		state := b.saveState(ctx, client, uid, expModel, records)
+		if revert != nil && len(records) > 0 {
+			if err := scheduleRevert(state, revert); err != nil {
+				// the experiment must not be left without the automatic revert
+				log.Errorf(ctx, "schedule the revert of %s failed, revert it in all the containers, %v", uid, err)
+				b.revert(ctx, client, uid, expModel, containers, records, succeeded, fn)
+				return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "ScheduleRevert", err)
+			}
+			return withRevert(revert, aggregateResponses(uid, expModel, containers, responses, nil))
+		}
+	} else if len(succeeded) > 0 {
+		log.Warnf(ctx, "the experiment %s failed in %d containers, revert it in the other %d containers", uid, len(failed), len(succeeded))
+		reverted := make(map[int]bool, len(succeeded))
+		for _, idx := range b.revert(ctx, client, uid, expModel, containers, records, succeeded, fn) {
+			reverted[idx] = true
+		}
+		return aggregateResponses(uid, expModel, containers, responses, reverted)
+	}
+	return aggregateResponses(uid, expModel, containers, responses, nil)
+}
+
+// revert destroys the experiment in the injected containers of the indexes and returns the indexes of the reverted
+// ones. The containers which fail to revert are recorded, otherwise the state of the experiment is removed
+func (b *BaseClientExecutor) revert(ctx context.Context, client *ExperimentClient, uid string, expModel *spec.ExpModel,
+	containers []container.ContainerInfo, records []TargetContainer, indexes []int, fn ContainerExecFunc,
+) []int {
+	injected := make([]container.ContainerInfo, 0, len(indexes))
+	for _, idx := range indexes {
+		injected = append(injected, containers[idx])
+	}
+	_, responses := runInContainers(spec.SetDestroyFlag(ctx, uid), injected, fn)
+	reverted := make([]int, 0, len(indexes))
+	remaining := make([]TargetContainer, 0)
+	for i, response := range responses {
+		idx := indexes[i]
+		if response.Success {
+			reverted = append(reverted, idx)
+			continue
+		}
+		log.Errorf(ctx, "revert the experiment %s in the container %s failed, %s", uid, containers[idx].ContainerId, response.Err)
+		remaining = append(remaining, records[idx])
+	}
+	if len(remaining) == 0 {
+		b.releaseState(ctx, uid)
+	} else {
+		b.saveState(ctx, client, uid, expModel, remaining)
+	}
+	return reverted
+}
+
+// releaseState removes the state of the experiment which is reverted
+func (b *BaseClientExecutor) releaseState(ctx context.Context, uid string) {
+	if err := RemoveExperimentState(uid); err != nil {
+		log.Warnf(ctx, "remove the state of %s failed, %v", uid, err)
+	}
+}
+
+// saveState records the containers of the experiment. When destroying, the records written at creation are kept
+// for the containers, so they can be reverted by the same command
+func (b *BaseClientExecutor) saveState(ctx context.Context, client *ExperimentClient, uid string, expModel *spec.ExpModel, records []TargetContainer) *ExperimentState {
+	_, isDestroy := spec.IsDestroy(ctx)
+	var saved *ExperimentState
+	_, err := UpdateExperimentState(uid, func(state *ExperimentState, ok bool) (*ExperimentState, error) {
+		if isDestroy && ok {
+			created := make(map[string]TargetContainer, len(state.Containers))
+			for _, record := range state.Containers {
+				created[record.key()] = record
+			}
+			for idx, record := range records {
+				if createdRecord, ok := created[record.key()]; ok {
+					records[idx] = createdRecord
+				}
+			}
+			state.Containers = records
+		} else {
+			state = b.newExperimentState(client, uid, expModel, records)
+		}
+		saved = state
+		return state, nil
+	})
+	if err != nil {
+		log.Warnf(ctx, "save the state of %s failed, %v", uid, err)
+	}
+	if saved == nil {
+		saved = b.newExperimentState(client, uid, expModel, records)
+	}
+	return saved
+}
+
+func (b *BaseClientExecutor) newExperimentState(client *ExperimentClient, uid string, expModel *spec.ExpModel, records []TargetContainer) *ExperimentState {
+	state := &ExperimentState{
+		Uid:        uid,
+		Target:     expModel.Target,
+		Action:     expModel.ActionName,
+		Executor:   b.Kind,
+		Runtime:    expModel.ActionFlags[ContainerRuntime.Name],
+		Endpoint:   expModel.ActionFlags[EndpointFlag.Name],
+		Namespace:  expModel.ActionFlags[ContainerNamespace.Name],
+		Flags:      make(map[string]string, len(expModel.ActionFlags)),
+		Containers: records,
+	}
+	if client.Detection != nil {
+		state.Runtime = client.Detection.Runtime
+		state.Endpoint = client.Detection.Endpoint
+	}
+	for k, v := range expModel.ActionFlags {
+		if v != "" {
+			state.Flags[k] = v
+		}
+	}
+	return state
+}
+
+// runInContainers executes the function in the containers at most FanOutParallelism at the same time,
+// the executions in the containers are recorded
+func runInContainers(ctx context.Context, containers []container.ContainerInfo, fn ContainerExecFunc) ([]TargetContainer, []*spec.Response) {
+	records := make([]TargetContainer, len(containers))
+	responses := make([]*spec.Response, len(containers))
+	parallelism := FanOutParallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for idx := range containers {
+		records[idx] = TargetContainer{
+			ContainerId:   containers[idx].ContainerId,
+			ContainerName: containers[idx].ContainerName,
+			Namespace:     containers[idx].Namespace,
+			Pid:           containers[idx].Pid,
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer func() {
+				if r := recover(); r != nil {
+					responses[idx] = spec.ResponseFailWithFlags(spec.ContainerExecFailed, containers[idx].ContainerId, r)
+				}
+				<-sem
+				wg.Done()
+			}()
+			// the namespace of the container is pinned, the id may exist in the other containerd namespaces
+			containerCtx := container.WithTargetNamespace(withContainerRecord(ctx, &records[idx]), containers[idx].Namespace)
+			responses[idx] = fn(containerCtx, containers[idx])
+			if responses[idx] == nil {
+				responses[idx] = spec.ResponseFailWithFlags(spec.ContainerExecFailed, containers[idx].ContainerId, "empty response")
+			}
+		}(idx)
+	}
+	wg.Wait()
+	return records, responses
+}
+
+// aggregateResponses returns the response of the only container directly, or the results of all the containers.
+// The pod of the only container is added to the response if the containers are resolved by the pod flags.
+// The containers of the reverted indexes are reported as not succeeded, the experiment is not active in them
+func aggregateResponses(uid string, expModel *spec.ExpModel, containers []container.ContainerInfo, responses []*spec.Response,
+	reverted map[int]bool,
+) *spec.Response {
+	if len(responses) == 0 {
+		return spec.ReturnSuccess(uid)
+	}
+	if len(responses) == 1 {
+		if pod := container.GetPodIdentity(containers[0].Labels); pod != nil && isPodTargeting(expModel) {
+			return withExperimentResult(responses[0], func(result *ExperimentResult) {
+				result.Pod = pod
+			})
+		}
+		return responses[0]
+	}
+	results := make([]ContainerResult, 0, len(responses))
+	failures := make([]string, 0)
+	var code int32
+	for idx, response := range responses {
+		result := ContainerResult{
+			ContainerId:   containers[idx].ContainerId,
+			ContainerName: containers[idx].ContainerName,
+			Success:       response.Success,
+			Code:          response.Code,
+			Err:           response.Err,
+			Result:        response.Result,
+			Pod:           container.GetPodIdentity(containers[idx].Labels),
+		}
+		if reverted[idx] {
+			result.Success = false
+			result.Reverted = true
+			result.Err = "the experiment is reverted, because it failed in the other containers"
+		}
+		results = append(results, result)
+		if !response.Success {
+			if code == 0 {
+				code = response.Code
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", containers[idx].ContainerId, response.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return spec.ReturnSuccess(results)
+	}
+	return &spec.Response{
+		Code:    code,
+		Success: false,
+		Err:     fmt.Sprintf("failed in %d of %d containers, %s", len(failures), len(responses), strings.Join(failures, "; ")),
+		Result:  results,
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// fakeContainer is the runtime client which lists the containers by the filter, the other methods are not used
+type fakeContainer struct {
+	container.Container
+	containers []container.ContainerInfo
+}
+
+func (f *fakeContainer) GetContainers(ctx context.Context, filter container.ContainerFilter) ([]container.ContainerInfo, error, int32) {
+	result := make([]container.ContainerInfo, 0)
+	for _, info := range f.containers {
+		if filter.Match(info) {
+			result = append(result, info)
+		}
+	}
+	return result, nil, spec.OK.Code
+}
+
+// useTempStateDir saves the experiment states in a temporary directory
+func useTempStateDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	root := stateRoot
+	stateRoot = func() string { return dir }
+	t.Cleanup(func() { stateRoot = root })
+}
+
+func TestRunInContainersPinsNamespace(t *testing.T) {
+	containers := []container.ContainerInfo{
+		{ContainerId: "abc", Namespace: "k8s.io"},
+		{ContainerId: "abc", Namespace: "default"},
+		{ContainerId: "xyz"},
+	}
+	var mu sync.Mutex
+	pinned := make(map[string]bool)
+	records, _ := runInContainers(context.Background(), containers, func(ctx context.Context, info container.ContainerInfo) *spec.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		pinned[container.TargetNamespace(ctx)+"/"+info.ContainerId] = true
+		return spec.ReturnSuccess(info.ContainerId)
+	})
+	for _, key := range []string{"k8s.io/abc", "default/abc", "/xyz"} {
+		if !pinned[key] {
+			t.Errorf("the container %s is not executed with its namespace, got %v", key, pinned)
+		}
+	}
+	for idx, record := range records {
+		if record.Namespace != containers[idx].Namespace {
+			t.Errorf("the record %d has the namespace %q, want %q", idx, record.Namespace, containers[idx].Namespace)
+		}
+	}
+}
+
+func TestGetExperimentContainersFromState(t *testing.T) {
+	useTempStateDir(t)
+	client := &fakeContainer{containers: []container.ContainerInfo{
+		{ContainerId: "abc", Namespace: "k8s.io", Labels: map[string]string{"app": "nginx"}},
+		{ContainerId: "abc", Namespace: "default", Labels: map[string]string{"app": "nginx"}},
+		{ContainerId: "xyz", Namespace: "default", Labels: map[string]string{"app": "redis"}},
+	}}
+	state := &ExperimentState{
+		Uid:    "uid-state",
+		Target: "cpu",
+		Action: "fullload",
+		Containers: []TargetContainer{
+			{ContainerId: "abc", Namespace: "default"},
+			{ContainerId: "gone", Namespace: "default"},
+		},
+	}
+	if err := saveExperimentState(state); err != nil {
+		t.Fatal(err)
+	}
+	// the selector is changed, the recorded containers are still destroyed
+	expModel := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: map[string]string{
+		ContainerLabelSelectorFlag.Name: "app=redis",
+	}}
+	ctx := spec.SetDestroyFlag(context.Background(), state.Uid)
+	executor := &BaseClientExecutor{}
+	containers, response := executor.GetExperimentContainers(ctx, client, state.Uid, expModel)
+	if !response.Success {
+		t.Fatalf("GetExperimentContainers() = %s", response.Err)
+	}
+	got := make([]string, 0, len(containers))
+	for _, ctr := range containers {
+		got = append(got, ctr.Namespace+"/"+ctr.ContainerId)
+	}
+	if strings.Join(got, ",") != "default/abc" {
+		t.Errorf("GetExperimentContainers() = %v, want the recorded default/abc", got)
+	}
+}
+
+func TestFanOutPartialFailure(t *testing.T) {
+	containers := []container.ContainerInfo{{ContainerId: "good"}, {ContainerId: "bad"}, {ContainerId: "stuck"}}
+	tests := []struct {
+		name         string
+		revertFailed map[string]bool
+		wantResults  map[string]ContainerResult
+		wantState    []string
+	}{
+		{
+			name: "the injected containers are reverted",
+			wantResults: map[string]ContainerResult{
+				"good":  {Success: false, Reverted: true},
+				"bad":   {Success: false},
+				"stuck": {Success: false, Reverted: true},
+			},
+		},
+		{
+			name:         "the container which fails to revert is recorded",
+			revertFailed: map[string]bool{"stuck": true},
+			wantResults: map[string]ContainerResult{
+				"good":  {Success: false, Reverted: true},
+				"bad":   {Success: false},
+				"stuck": {Success: true},
+			},
+			wantState: []string{"stuck"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempStateDir(t)
+			uid := "uid-fanout"
+			client := &ExperimentClient{Container: &fakeContainer{containers: containers}}
+			executor := &BaseClientExecutor{Kind: ExecutorKindNSExec}
+			expModel := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: map[string]string{}}
+			response := executor.FanOut(context.Background(), client, uid, expModel, containers,
+				func(ctx context.Context, info container.ContainerInfo) *spec.Response {
+					if _, isDestroy := spec.IsDestroy(ctx); isDestroy {
+						if tt.revertFailed[info.ContainerId] {
+							return spec.ResponseFailWithFlags(spec.ContainerExecFailed, info.ContainerId, "revert failed")
+						}
+						return spec.ReturnSuccess(info.ContainerId)
+					}
+					if info.ContainerId == "bad" {
+						return spec.ResponseFailWithFlags(spec.ContainerExecFailed, info.ContainerId, "inject failed")
+					}
+					return spec.ReturnSuccess(info.ContainerId)
+				})
+			if response.Success {
+				t.Fatal("FanOut() succeeds with a failed container")
+			}
+			results, ok := response.Result.([]ContainerResult)
+			if !ok {
+				t.Fatalf("FanOut() result = %T, want []ContainerResult", response.Result)
+			}
+			for _, result := range results {
+				want := tt.wantResults[result.ContainerId]
+				if result.Success != want.Success || result.Reverted != want.Reverted {
+					t.Errorf("the result of %s = success %v, reverted %v, want success %v, reverted %v",
+						result.ContainerId, result.Success, result.Reverted, want.Success, want.Reverted)
+				}
+			}
+
+			state, ok, err := LoadExperimentState(uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorded := make([]string, 0)
+			if ok {
+				for _, target := range state.Containers {
+					recorded = append(recorded, target.ContainerId)
+				}
+			}
+			if strings.Join(recorded, ",") != strings.Join(tt.wantState, ",") {
+				t.Errorf("the recorded containers = %v, want %v", recorded, tt.wantState)
+			}
+			if len(tt.wantState) == 0 {
+				return
+			}
+			// destroying reverts exactly the recorded containers
+			destroyCtx := spec.SetDestroyFlag(context.Background(), uid)
+			targets, response := executor.GetExperimentContainers(destroyCtx, client, uid, expModel)
+			if !response.Success {
+				t.Fatalf("GetExperimentContainers() = %s", response.Err)
+			}
+			destroyed := make([]string, 0, len(targets))
+			for _, target := range targets {
+				destroyed = append(destroyed, target.ContainerId)
+			}
+			if strings.Join(destroyed, ",") != strings.Join(tt.wantState, ",") {
+				t.Errorf("the containers to destroy = %v, want %v", destroyed, tt.wantState)
+			}
+		})
+	}
+}
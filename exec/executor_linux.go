@@ -35,19 +35,45 @@
 package exec
 
 import (
+	"context"
+
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/containerd"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/cri"
+	cri_o "github.com/chaosblade-io/chaosblade-exec-cri/exec/container/cri-o"
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/docker"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/podman"
 )
 
 func GetClientByRuntime(expModel *spec.ExpModel) (container.Container, error) {
-	switch expModel.ActionFlags[ContainerRuntime.Name] {
+	cli, _, err := getClient(context.Background(), expModel)
+	return cli, err
+}
+
+// getClient returns the client of the runtime flag, the runtime is detected automatically if the flag is omitted
+func getClient(ctx context.Context, expModel *spec.ExpModel) (container.Container, *RuntimeDetection, error) {
+	runtime := expModel.ActionFlags[ContainerRuntime.Name]
+	if runtime == "" {
+		return detectClient(ctx, expModel)
+	}
+	cli, err := newClient(runtime, expModel.ActionFlags[EndpointFlag.Name], expModel.ActionFlags[ContainerNamespace.Name])
+	return cli, nil, err
+}
+
+func newClient(runtime, endpoint, namespace string) (container.Container, error) {
+	switch runtime {
 	case container.ContainerdRuntime:
-		return containerd.NewClient(expModel.ActionFlags[EndpointFlag.Name], expModel.ActionFlags[ContainerNamespace.Name])
+		return containerd.NewClient(endpoint, namespace)
+	case container.CrioRuntime:
+		return cri_o.NewClient(endpoint)
+	case container.CriRuntime:
+		return cri.NewClient(endpoint)
+	case container.PodmanRuntime:
+		return podman.NewClient(endpoint)
 	default:
-		return docker.NewClient(expModel.ActionFlags[EndpointFlag.Name])
+		return docker.NewClient(endpoint)
 		// default:
 		//	return nil,errors.New(fmt.Sprintf("`%s`, the container runtime not support", expModel.ActionFlags[ContainerRuntime.Name]))
 	}
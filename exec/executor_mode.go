@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// The modes of executing the experiments, they are the same as the executor kinds recorded in the state
+const (
+	ExecModeNSExec  = ExecutorKindNSExec
+	ExecModeCopy    = ExecutorKindCopy
+	ExecModeSidecar = ExecutorKindSidecar
+)
+
+// ExecModeExecutor dispatches the experiment to the executor of the mode selected by the exec-mode flag.
+// When destroying, the mode recorded at creation is used
+type ExecModeExecutor struct {
+	executors map[string]spec.Executor
+}
+
+// NewExecModeExecutor returns the executor whose nsexec mode is executed by the executor, the copy mode is always
+// supported and the sidecar mode is supported only if the sidecar executor is not nil
+func NewExecModeExecutor(nsexec spec.Executor, sidecar spec.Executor) *ExecModeExecutor {
+	executors := map[string]spec.Executor{
+		ExecModeNSExec: nsexec,
+		ExecModeCopy:   NewRunCmdInContainerExecutorByCP(),
+	}
+	if sidecar != nil {
+		executors[ExecModeSidecar] = sidecar
+	}
+	return &ExecModeExecutor{executors: executors}
+}
+
+func (e *ExecModeExecutor) Name() string {
+	return "execModeExecutor"
+}
+
+func (e *ExecModeExecutor) SetChannel(channel spec.Channel) {
+	for _, executor := range e.executors {
+		executor.SetChannel(channel)
+	}
+}
+
+func (e *ExecModeExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
+	mode := e.execMode(ctx, uid, expModel)
+	executor, ok := e.executors[mode]
+	if !ok {
+		reason := fmt.Sprintf("the %s target supports the modes: %s", expModel.Target, strings.Join(e.modes(), ", "))
+		log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ExecModeFlag.Name, mode, reason))
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, ExecModeFlag.Name, mode, reason)
+	}
+	log.Debugf(ctx, "execute the experiment %s in the %s mode", uid, mode)
+	return executor.Exec(uid, ctx, expModel)
+}
+
+// execMode returns the mode of the experiment, the mode recorded in the state is preferred when destroying
+func (e *ExecModeExecutor) execMode(ctx context.Context, uid string, expModel *spec.ExpModel) string {
+	if _, ok := spec.IsDestroy(ctx); ok {
+		if state, ok, err := LoadExperimentState(uid); err == nil && ok && state.Executor != "" {
+			return state.Executor
+		}
+	}
+	if mode := expModel.ActionFlags[ExecModeFlag.Name]; mode != "" {
+		return mode
+	}
+	return ExecModeNSExec
+}
+
+func (e *ExecModeExecutor) modes() []string {
+	modes := make([]string, 0, len(e.executors))
+	for mode := range e.executors {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
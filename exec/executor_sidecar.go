@@ -29,6 +29,12 @@ import (
 	execContainer "github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
 )
 
+// The label of the sidecar containers
+const (
+	SidecarLabelKey   = "chaosblade"
+	SidecarLabelValue = "chaosblade-sidecar"
+)
+
 type RunInSidecarContainerExecutor struct {
 	BaseClientExecutor
 	runConfigFunc func(container string) (container.HostConfig, network.NetworkingConfig)
@@ -39,21 +45,22 @@ func (*RunInSidecarContainerExecutor) Name() string {
 	return "runAndExecSidecar"
 }
 
-func (r *RunInSidecarContainerExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
-	if err := r.SetClient(expModel); err != nil {
+func (r *RunInSidecarContainerExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) (response *spec.Response) {
+	client, err := r.PrepareClient(ctx, uid, expModel)
+	if err != nil {
 		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("GetClient", err))
 		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "GetClient", err)
 	}
-	containerId := expModel.ActionFlags[ContainerIdFlag.Name]
-	containerName := expModel.ActionFlags[ContainerNameFlag.Name]
-	containerLabelSelector := parseContainerLabelSelector(expModel.ActionFlags[ContainerLabelSelectorFlag.Name])
-	containerInfo, response := GetContainer(ctx, r.Client, uid, containerId, containerName, containerLabelSelector)
+	defer func() { response = client.WithDetection(response) }()
+	containers, response := r.GetExperimentContainers(ctx, client, uid, expModel)
 	if !response.Success {
 		return response
 	}
-	hostConfig, networkingConfig := r.runConfigFunc(containerInfo.ContainerId)
-	sidecarName := createSidecarContainerName(containerInfo.ContainerName, expModel.Target, expModel.ActionName)
-	return r.startAndExecInContainer(uid, ctx, expModel, &hostConfig, &networkingConfig, sidecarName, containerInfo)
+	return r.FanOut(ctx, client, uid, expModel, containers, func(ctx context.Context, containerInfo execContainer.ContainerInfo) *spec.Response {
+		hostConfig, networkingConfig := r.runConfigFunc(containerInfo.ContainerId)
+		sidecarName := createSidecarContainerName(containerInfo.ContainerName, expModel.Target, expModel.ActionName)
+		return r.startAndExecInContainer(uid, ctx, client, expModel, &hostConfig, &networkingConfig, sidecarName, containerInfo)
+	})
 }
 
 func NewNetWorkSidecarExecutor() *RunInSidecarContainerExecutor {
@@ -71,6 +78,7 @@ func NewNetWorkSidecarExecutor() *RunInSidecarContainerExecutor {
 		isResident:    false,
 		BaseClientExecutor: BaseClientExecutor{
 			CommandFunc: CommonFunc,
+			Kind:        ExecutorKindSidecar,
 		},
 	}
 }
@@ -92,25 +100,26 @@ func (r *RunInSidecarContainerExecutor) getContainerConfig(expModel *spec.ExpMod
 		Image: execContainer.GetChaosBladeImageRef(expModel.ActionFlags[ImageRepoFlag.Name],
 			expModel.ActionFlags[ImageVersionFlag.Name]),
 		Labels: map[string]string{
-			"chaosblade": "chaosblade-sidecar",
+			SidecarLabelKey: SidecarLabelValue,
 		},
 	}
 }
 
-func (r *RunInSidecarContainerExecutor) startAndExecInContainer(uid string, ctx context.Context, expModel *spec.ExpModel,
+func (r *RunInSidecarContainerExecutor) startAndExecInContainer(uid string, ctx context.Context, client execContainer.Container, expModel *spec.ExpModel,
 	hostConfig *container.HostConfig, networkConfig *network.NetworkingConfig, containerName string, containerInfo execContainer.ContainerInfo,
 ) *spec.Response {
 	config := r.getContainerConfig(expModel)
-	var defaultResponse *spec.Response
 	command := r.CommandFunc(uid, ctx, expModel)
-	sidecarContainerId, output, err, code := r.Client.ExecuteAndRemove(ctx,
+	recordContainerExec(ctx, containerInfo.Pid, []string{"/bin/sh", "-c", command})
+	sidecarContainerId, result, err, code := client.ExecuteAndRemove(ctx,
 		config, hostConfig, networkConfig, containerName, true, time.Second, command, containerInfo)
 
 	if err != nil {
 		log.Errorf(ctx, "%s", err.Error())
 		return spec.ResponseFail(code, err.Error(), nil)
 	}
-	returnedResponse := ConvertContainerOutputToResponse(output, err, defaultResponse)
-	log.Infof(ctx, "sidecarContainerId for experiment %s is %s, output is %s, err is %v", uid, sidecarContainerId, output, err)
+	returnedResponse := ConvertExecResultToResponse(result, err)
+	log.Infof(ctx, "sidecarContainerId for experiment %s is %s, output is %s, exit code is %d", uid, sidecarContainerId,
+		result.Output(), result.ExitCode)
 	return returnedResponse
 }
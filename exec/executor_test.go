@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+func TestConvertExecResultToResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  container.ExecResult
+		err     error
+		success bool
+		code    int32
+	}{
+		{
+			name:    "response after the marker",
+			result:  container.ExecResult{Stdout: "CHAOSBLADE_RESPONSE\n{\"code\":200,\"success\":true,\"result\":\"uid\"}\n"},
+			success: true,
+			code:    200,
+		},
+		{
+			name: "output of the shell profile before the marker",
+			result: container.ExecResult{
+				Stdout: "{\"code\":200,\"success\":true}\nCHAOSBLADE_RESPONSE\n{\"code\":63020,\"success\":false,\"error\":\"failed\"}\n",
+			},
+			code: 63020,
+		},
+		{
+			name:   "no marker",
+			result: container.ExecResult{Stdout: "{\"code\":200,\"success\":true}"},
+			code:   spec.ContainerExecFailed.Code,
+		},
+		{
+			name:   "extra output after the response",
+			result: container.ExecResult{Stdout: "CHAOSBLADE_RESPONSE\n{\"code\":200,\"success\":true}\ndone\n"},
+			code:   spec.ContainerExecFailed.Code,
+		},
+		{
+			name:   "successful response with the non-zero exit code",
+			result: container.ExecResult{ExitCode: 1, Stdout: "CHAOSBLADE_RESPONSE\n{\"code\":200,\"success\":true}"},
+			code:   spec.ContainerExecFailed.Code,
+		},
+		{
+			name: "failed response in the stderr",
+			result: container.ExecResult{
+				ExitCode: 1,
+				Stdout:   "CHAOSBLADE_RESPONSE\n",
+				Stderr:   "{\"code\":63020,\"success\":false,\"error\":\"failed\"}",
+			},
+			code: 63020,
+		},
+		{
+			name:   "json which is not a response",
+			result: container.ExecResult{Stdout: "CHAOSBLADE_RESPONSE\n{\"name\":\"blade\"}"},
+			code:   spec.ContainerExecFailed.Code,
+		},
+		{
+			name:   "empty output",
+			result: container.ExecResult{Stdout: "CHAOSBLADE_RESPONSE\n"},
+			code:   spec.ContainerExecFailed.Code,
+		},
+		{
+			name: "exec error",
+			err:  errors.New("connection refused"),
+			code: spec.ContainerExecFailed.Code,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ConvertExecResultToResponse(tt.result, tt.err)
+			if response.Success != tt.success || response.Code != tt.code {
+				t.Errorf("got success %v and code %d, want success %v and code %d, response: %s",
+					response.Success, response.Code, tt.success, tt.code, response.Print())
+			}
+		})
+	}
+}
+
+func TestConvertContainerOutputToResponse(t *testing.T) {
+	defaultResponse := spec.ReturnSuccess("default")
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected *spec.Response
+		success  bool
+		code     int32
+	}{
+		{
+			name:    "response",
+			output:  "{\"code\":200,\"success\":true}",
+			success: true,
+			code:    200,
+		},
+		{
+			name:    "successful response in the error",
+			err:     errors.New("{\"code\":200,\"success\":true}"),
+			success: true,
+			code:    200,
+		},
+		{
+			name: "error",
+			err:  errors.New("exit status 1"),
+			code: spec.ContainerExecFailed.Code,
+		},
+		{
+			name:     "default response",
+			output:   "done",
+			expected: defaultResponse,
+			success:  true,
+			code:     200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ConvertContainerOutputToResponse(tt.output, tt.err, defaultResponse)
+			if tt.expected != nil && response != tt.expected {
+				t.Errorf("got %s, want the default response", response.Print())
+			}
+			if response.Success != tt.success || response.Code != tt.code {
+				t.Errorf("got success %v and code %d, want success %v and code %d, response: %s",
+					response.Success, response.Code, tt.success, tt.code, response.Print())
+			}
+		})
+	}
+}
+
+func TestCommonFunc(t *testing.T) {
+	model := &spec.ExpModel{
+		Target:      "cpu",
+		ActionName:  "fullload",
+		ActionFlags: map[string]string{"cpu-count": "1"},
+	}
+	expected := "echo CHAOSBLADE_RESPONSE; exec /opt/chaosblade/blade create cpu fullload --cpu-count=1 --uid uid"
+	if command := CommonFunc("uid", context.Background(), model); command != expected {
+		t.Errorf("got %q, want %q", command, expected)
+	}
+}
+
+func TestExperimentResult(t *testing.T) {
+	revertTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	response := spec.ReturnSuccess("uid")
+	response = withRevert(&ScheduledRevert{RevertTime: revertTime, Lease: "30s"}, response)
+	response = withDetection(&RuntimeDetection{Runtime: "containerd", Endpoint: "/run/containerd/containerd.sock"}, response)
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"runtime":"containerd","endpoint":"/run/containerd/containerd.sock","revertTime":"2025-01-02T03:04:05Z","lease":"30s","result":"uid"}`
+	if string(data) != expected {
+		t.Errorf("got %s, want %s", data, expected)
+	}
+
+	unchanged := withRevert(nil, withDetection(nil, spec.ReturnSuccess("uid")))
+	if result, ok := unchanged.Result.(string); !ok || result != "uid" {
+		t.Errorf("the result is wrapped without any context, %v", unchanged.Result)
+	}
+}
+
+func TestCheckAmbiguousContainers(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []container.ContainerInfo
+		want       string
+	}{
+		{"one container", []container.ContainerInfo{{ContainerId: "abc"}}, ""},
+		{"different containers", []container.ContainerInfo{{ContainerId: "abc"}, {ContainerId: "abd"}},
+			"please specify the container-multi-target flag"},
+		{"same id in different namespaces", []container.ContainerInfo{
+			{ContainerId: "abc", Namespace: "k8s.io"}, {ContainerId: "abc", Namespace: "default"},
+		}, "the container abc is found in the containerd namespaces k8s.io, default, please specify the container-namespace flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := checkAmbiguousContainers(context.Background(), container.ContainerFilter{ContainerId: "ab"}, tt.containers)
+			if tt.want == "" {
+				if response != nil {
+					t.Errorf("checkAmbiguousContainers() = %s, want nil", response.Err)
+				}
+				return
+			}
+			if response == nil || !strings.Contains(response.Err, tt.want) {
+				t.Errorf("checkAmbiguousContainers() = %v, want %q", response, tt.want)
+			}
+		})
+	}
+}
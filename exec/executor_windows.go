@@ -35,6 +35,7 @@
 package exec
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -57,6 +58,31 @@ func GetClientByRuntime(expModel *spec.ExpModel) (container.Container, error) {
 	return nil, fmt.Errorf("container runtime not fully supported on Windows platform")
 }
 
+func getClient(ctx context.Context, expModel *spec.ExpModel) (container.Container, *RuntimeDetection, error) {
+	cli, err := GetClientByRuntime(expModel)
+	return cli, nil, err
+}
+
+// isExperimentProcessAlive returns false, the processes of the experiments are only started on linux
+func isExperimentProcessAlive(pid int, uid string) bool {
+	return false
+}
+
+// killExperimentProcess does nothing, the processes of the experiments are only started on linux
+func killExperimentProcess(pid int, uid string) (bool, error) {
+	return false, nil
+}
+
+// lockStateDir does nothing, the experiments are not executed by the concurrent processes on windows
+func lockStateDir(dir string) (func(), error) {
+	return func() {}, nil
+}
+
+// startRevertWatcher is not supported on windows
+func startRevertWatcher(uid, file string) (int, error) {
+	return 0, fmt.Errorf("the revert watcher is not supported on windows")
+}
+
 func NewCriExpModelSpec() *DockerExpModelSpec {
 	// Windows implementation - return empty model spec
 	return &DockerExpModelSpec{
@@ -92,7 +118,7 @@ var ContainerNameFlag = &spec.ExpFlag{
 
 var ContainerLabelSelectorFlag = &spec.ExpFlag{
 	Name:                  "container-label-selector",
-	Desc:                  "Container label selector, when used with container-id or container-name, container-id or container-name is preferred",
+	Desc:                  "Container label selector, supports the kubernetes syntax, such as `app=web,tier notin (db),canary,!legacy`. When used with container-id or container-name, container-id or container-name is preferred. Specify container-multi-target to inject all the matched containers",
 	NoArgs:                false,
 	Required:              false,
 	RequiredWhenDestroyed: false,
@@ -107,14 +133,124 @@ var EndpointFlag = &spec.ExpFlag{
 
 var ContainerRuntime = &spec.ExpFlag{
 	Name:     "container-runtime",
-	Desc:     "container runtime, support cri and containerd, default value is docker",
+	Desc:     "container runtime, support docker, containerd, crio, cri and podman, the runtime is detected automatically if omitted. cri talks to any runtime by the kubernetes CRI api",
 	NoArgs:   false,
 	Required: false,
 }
 
 var ContainerNamespace = &spec.ExpFlag{
 	Name:     "container-namespace",
-	Desc:     "container namespace, If container-runtime is containerd it will be used, default value is k8s.io",
+	Desc:     "container namespace, If container-runtime is containerd it will be used, all namespaces are searched for the container if omitted",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerImageFlag = &spec.ExpFlag{
+	Name:     "container-image",
+	Desc:     "Container image, the image reference such as redis:7, or the regular expression of the image with the regex: prefix such as `regex:.*/redis:7.*`. It works together with the other container flags",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerNamePatternFlag = &spec.ExpFlag{
+	Name:     "container-name-pattern",
+	Desc:     "Container name pattern, the glob pattern such as payment-*, or the regular expression with the regex: prefix such as regex:^payment-v[0-9]+$. It works together with the other container flags",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExcludeContainerLabelFlag = &spec.ExpFlag{
+	Name:     "exclude-container-label",
+	Desc:     "Exclude the containers whose labels match the label selector, such as track=canary",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExcludeContainerNameFlag = &spec.ExpFlag{
+	Name:     "exclude-container-name",
+	Desc:     "Exclude the containers whose names match any of the names or the name patterns, separated by commas, such as `web-1,canary-*`. A regex: pattern may contain commas, so it must be the last one, such as `web-1,regex:^(a{1,3}|b)$`",
+	NoArgs:   false,
+	Required: false,
+}
+
+var PodNamespaceFlag = &spec.ExpFlag{
+	Name:     "pod-namespace",
+	Desc:     "The kubernetes namespace of the pod, it is resolved by the io.kubernetes.pod.namespace label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var PodNameFlag = &spec.ExpFlag{
+	Name:     "pod-name",
+	Desc:     "The kubernetes pod name, it is resolved by the io.kubernetes.pod.name label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var K8sContainerNameFlag = &spec.ExpFlag{
+	Name:     "k8s-container-name",
+	Desc:     "The container name in the kubernetes pod, it is resolved by the io.kubernetes.container.name label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var AllowPausedContainerFlag = &spec.ExpFlag{
+	Name:   "allow-paused-container",
+	Desc:   "Allow the experiment in the paused containers, the containers which are not running are refused by default",
+	NoArgs: true,
+}
+
+var LeaseFlag = &spec.ExpFlag{
+	Name:     "lease",
+	Desc:     "The lease of the experiment, such as 30s or 5m. The experiment is reverted automatically if the lease is not renewed in time by blade query cri renew --experiment-uid <uid>",
+	NoArgs:   false,
+	Required: false,
+}
+
+var NsFlag = &spec.ExpFlag{
+	Name:     "ns",
+	Desc:     "The namespaces of the container process which the experiment is executed in, separated by comma, such as pid,mnt,ipc. The supported namespaces are pid, mnt, net, ipc, uts, cgroup and user, the default namespaces are declared by the action",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExperimentUidFlag = &spec.ExpFlag{
+	Name:     "experiment-uid",
+	Desc:     "The uid of the cri experiment",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExecModeFlag = &spec.ExpFlag{
+	Name:     "exec-mode",
+	Desc:     "The mode of executing the experiment, the values are nsexec, copy and sidecar, default value is nsexec. The copy mode copies the chaosblade tool into the container, the sidecar mode executes the experiment in a sidecar container which joins the network of the container",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerMultiTargetFlag = &spec.ExpFlag{
+	Name:   "container-multi-target",
+	Desc:   "Allow the container flags to match multiple containers, the experiment is applied to all of them, default value is false",
+	NoArgs: true,
+}
+
+var ContainerCountFlag = &spec.ExpFlag{
+	Name:     "container-count",
+	Desc:     "Randomly pick the number of the matched containers as the targets",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerPercentFlag = &spec.ExpFlag{
+	Name:     "container-percent",
+	Desc:     "Randomly pick the percentage of the matched containers as the targets, the value is in (0, 100]",
+	NoArgs:   false,
+	Required: false,
+}
+
+var SeedFlag = &spec.ExpFlag{
+	Name:     "seed",
+	Desc:     "The random seed of picking the containers by container-count or container-percent, the same seed picks the same containers",
 	NoArgs:   false,
 	Required: false,
 }
@@ -152,6 +288,21 @@ func GetNSExecFlags() []spec.ExpFlagSpec {
 		ContainerRuntime,
 		ContainerNamespace,
 		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
+		LeaseFlag,
+		NsFlag,
+		ExecModeFlag,
+		AllowPausedContainerFlag,
 	}
 }
 
@@ -164,6 +315,21 @@ func GetAllDockerFlagNames() map[string]spec.Empty {
 		ContainerRuntime,
 		ContainerNamespace,
 		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
+		LeaseFlag,
+		NsFlag,
+		ExecModeFlag,
+		AllowPausedContainerFlag,
 		ImageRepoFlag,
 		ImageVersionFlag,
 		ChaosBladeReleaseFlag,
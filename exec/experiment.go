@@ -0,0 +1,129 @@
+//go:build linux || darwin
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// ExperimentCommand manages the cri experiments on the node. It is not an experiment, so it is executed by the
+// query commands of blade, such as `blade query cri status`, instead of `blade create` which records every
+// execution as a new experiment
+type ExperimentCommand struct {
+	Name      string
+	Aliases   []string
+	ShortDesc string
+	LongDesc  string
+	Example   string
+	Flags     []spec.ExpFlagSpec
+	// Run executes the command with the flags, the flags are keyed by the flag name
+	Run func(ctx context.Context, flags map[string]string) *spec.Response
+}
+
+// ExperimentCommands returns the commands which query, renew, resume or destroy the cri experiments on the node
+func ExperimentCommands() []*ExperimentCommand {
+	return []*ExperimentCommand{
+		{
+			Name:      "status",
+			Aliases:   []string{"query"},
+			ShortDesc: "query the status of the cri experiments",
+			LongDesc:  "Query the status of the cri experiment, or of all the active cri experiments if the experiment-uid is not specified",
+			Example: `# Query the status of all the active cri experiments on the node
+blade query cri status
+
+# Query the status of the experiment whose uid is 4f2ea1f9b5e0bd8a
+blade query cri status --experiment-uid 4f2ea1f9b5e0bd8a`,
+			Flags: []spec.ExpFlagSpec{ExperimentUidFlag},
+			Run: func(ctx context.Context, flags map[string]string) *spec.Response {
+				return QueryExperiments(ctx, flags[ExperimentUidFlag.Name])
+			},
+		},
+		{
+			Name:      "destroy-all",
+			Aliases:   []string{},
+			ShortDesc: "destroy all the cri experiments",
+			LongDesc:  "Destroy all the cri experiments on the node regardless of their flags, it can be executed again to retry the failed experiments",
+			Example: `# Destroy all the cri experiments on the node and remove the leftover sidecar containers
+blade query cri destroy-all`,
+			Flags: []spec.ExpFlagSpec{},
+			Run: func(ctx context.Context, flags map[string]string) *spec.Response {
+				return DestroyAllExperiments(ctx)
+			},
+		},
+		{
+			Name:      "resume",
+			Aliases:   []string{},
+			ShortDesc: "resume the revert watchers of the cri experiments",
+			LongDesc:  "Resume the revert watchers which are not running, such as after the node is rebooted, the experiments whose lease expired are reverted immediately",
+			Example: `# Resume the revert watchers after the node is rebooted
+blade query cri resume`,
+			Flags: []spec.ExpFlagSpec{},
+			Run: func(ctx context.Context, flags map[string]string) *spec.Response {
+				return ResumeExperimentWatchers(ctx)
+			},
+		},
+		{
+			Name:      "renew",
+			Aliases:   []string{"heartbeat"},
+			ShortDesc: "renew the lease of the cri experiment",
+			LongDesc:  "Renew the lease of the cri experiment which is created with the lease flag, the experiment is reverted if the lease is not renewed in time",
+			Example: `# Renew the lease of the experiment whose uid is 4f2ea1f9b5e0bd8a
+blade query cri renew --experiment-uid 4f2ea1f9b5e0bd8a`,
+			Flags: []spec.ExpFlagSpec{
+				&spec.ExpFlag{
+					Name:     ExperimentUidFlag.Name,
+					Desc:     "The uid of the cri experiment whose lease is renewed",
+					NoArgs:   false,
+					Required: true,
+				},
+			},
+			Run: func(ctx context.Context, flags map[string]string) *spec.Response {
+				return RenewExperimentLease(ctx, flags[ExperimentUidFlag.Name])
+			},
+		},
+	}
+}
+
+// GetExperimentCommand returns the command by its name or alias, nil if there is no such command
+func GetExperimentCommand(name string) *ExperimentCommand {
+	for _, command := range ExperimentCommands() {
+		if command.Name == name {
+			return command
+		}
+		for _, alias := range command.Aliases {
+			if alias == name {
+				return command
+			}
+		}
+	}
+	return nil
+}
+
+// ExecExperimentCommand executes the command which manages the cri experiments, no experiment is created for it
+func ExecExperimentCommand(ctx context.Context, name string, flags map[string]string) *spec.Response {
+	command := GetExperimentCommand(name)
+	if command == nil {
+		log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf("command", name, "unknown cri experiment command"))
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, "command", name, "unknown cri experiment command")
+	}
+	return command.Run(ctx, flags)
+}
@@ -0,0 +1,65 @@
+//go:build linux || darwin
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestExecExperimentCommand(t *testing.T) {
+	useTempStateDir(t)
+	tests := []struct {
+		name    string
+		command string
+		flags   map[string]string
+		success bool
+		code    int32
+	}{
+		{name: "status", command: "status", flags: map[string]string{}, success: true},
+		{name: "alias", command: "query", flags: map[string]string{}, success: true},
+		{name: "unknown", command: "unknown", flags: map[string]string{}, code: spec.ParameterInvalid.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := ExecExperimentCommand(context.Background(), tt.command, tt.flags)
+			if response.Success != tt.success {
+				t.Fatalf("expect success %v, got %+v", tt.success, response)
+			}
+			if !tt.success && response.Code != tt.code {
+				t.Errorf("expect code %d, got %d", tt.code, response.Code)
+			}
+		})
+	}
+}
+
+func TestExperimentCommandsAreNotExperiments(t *testing.T) {
+	for _, modelSpec := range []*DockerExpModelSpec{NewCriExpModelSpec(), NewDockerExpModelSpec()} {
+		for _, expModel := range modelSpec.ExpModels() {
+			if GetExperimentCommand(expModel.Name()) != nil {
+				t.Errorf("the command %s is registered as an experiment target", expModel.Name())
+			}
+			if expModel.Name() == "experiment" {
+				t.Errorf("the experiment commands are registered as the target %s", expModel.Name())
+			}
+		}
+	}
+}
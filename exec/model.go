@@ -58,7 +58,9 @@ type ResourceExpModelSpec interface {
 
 func newNetworkCommandModelSpecForDocker() spec.ExpModelCommandSpec {
 	networkCommandModelSpec := network.NewNetworkCommandSpec()
-	for _, action := range networkCommandModelSpec.Actions() {
+	actions := networkCommandModelSpec.Actions()
+	for idx, action := range actions {
+		namespaces := NetworkNamespaces
 		v := interface{}(action)
 		switch v.(type) {
 		case *tc.DelayActionSpec:
@@ -79,6 +81,8 @@ blade create cri network drop --source-port 80 --network-traffic in --container-
 			action.SetExample(
 				`# The domain name www.baidu.com is not accessible
 blade create cri network dns --domain www.baidu.com --ip 10.0.0.0 --container-id ee54f1e61c08`)
+			// the hosts file is modified in the mnt namespace
+			namespaces = []string{NamespacePid, NamespaceMnt}
 		case *tc.LossActionSpec:
 			action.SetExample(`# Access to native 8080 and 8081 ports lost 70% of packets
 blade create cri network loss --percent 70 --interface eth0 --local-port 8080,8081 --container-id ee54f1e61c08
@@ -106,14 +110,18 @@ blade create cri network occupy --port 8080 --force --container-id ee54f1e61c08
 
 # The machine accesses external 14.215.177.39 machine (ping www.baidu.com) 80 port packet loss rate 100%
 blade create cri network loss --percent 100 --interface eth0 --remote-port 80 --destination-ip 14.215.177.39 --container-id ee54f1e61c08`)
+			// the port is occupied by the process started in the net namespace
+			namespaces = []string{NamespacePid, NamespaceMnt, NamespaceNet}
 		}
+		actions[idx] = NewNamespacedActionSpec(action, namespaces...)
 	}
 	return networkCommandModelSpec
 }
 
 func newFileCommandSpecForDocker() spec.ExpModelCommandSpec {
 	fileCommandSpec := file.NewFileCommandSpec()
-	for _, action := range fileCommandSpec.Actions() {
+	actions := fileCommandSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *file.FileAppendActionSpec:
@@ -173,13 +181,15 @@ blade create cri file delete --filepath /home/logs/nginx.log --target /tmp --for
 blade create cri file delete --filepath /home/logs/nginx.log --target /temp --auto-create-dir --chaosblade-release /root/chaosblade-0.6.0.tar.gz --container-id ee54f1e61c08
 `)
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return fileCommandSpec
 }
 
 func newMemCommandModelSpecForDocker() spec.ExpModelCommandSpec {
 	memCommandModelSpec := mem.NewMemCommandModelSpec()
-	for _, action := range memCommandModelSpec.Actions() {
+	actions := memCommandModelSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *mem.MemLoadActionCommand:
@@ -200,13 +210,15 @@ blade create cri mem load --mode ram --mem-percent 50 --timeout 200 --chaosblade
 # 200M memory is reserved
 blade create cri mem load --mode ram --reserve 200 --rate 100 --chaosblade-release /root/chaosblade-0.6.0.tar.gz --container-id ee54f1e61c08`)
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return memCommandModelSpec
 }
 
 func newDiskFillCommandSpecForDocker() spec.ExpModelCommandSpec {
 	commandSpec := disk.NewDiskCommandSpec()
-	for _, action := range commandSpec.Actions() {
+	actions := commandSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *disk.FillActionSpec:
@@ -234,13 +246,15 @@ blade create cri disk burn --write --path /home --chaosblade-release /root/chaos
 # Read and write IO load scenarios are performed at the same time. Path is not specified. The default is /
 blade create cri disk burn --read --write --chaosblade-release /root/chaosblade-0.6.0.tar.gz --container-id ee54f1e61c08`)
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return commandSpec
 }
 
 func newDiskCommandSpecForDocker() spec.ExpModelCommandSpec {
 	commandSpec := disk.NewDiskCommandSpec()
-	for _, action := range commandSpec.Actions() {
+	actions := commandSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *disk.FillActionSpec:
@@ -268,13 +282,15 @@ blade create cri disk burn --write --path /home --chaosblade-release /root/chaos
 # Read and write IO load scenarios are performed at the same time. Path is not specified. The default is /
 blade create cri disk burn --read --write --chaosblade-release /root/chaosblade-0.6.0.tar.gz --container-id ee54f1e61c08`)
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return commandSpec
 }
 
 func newCpuCommandModelSpecForDocker() spec.ExpModelCommandSpec {
 	cpuCommandModelSpec := cpu.NewCpuCommandModelSpec()
-	for _, action := range cpuCommandModelSpec.Actions() {
+	actions := cpuCommandModelSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *cpu.FullLoadActionCommand:
@@ -298,13 +314,15 @@ blade create cri cpu load --cpu-list 1-3 --container-id ee54f1e61c08
 # Specified percentage load in the container (Linux)
 blade create cri cpu load --cpu-percent 60 --container-id ee54f1e61c08`)
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return cpuCommandModelSpec
 }
 
 func newProcessCommandModelSpecForDocker() spec.ExpModelCommandSpec {
 	commandModelSpec := process.NewProcessCommandModelSpec()
-	for _, action := range commandModelSpec.Actions() {
+	actions := commandModelSpec.Actions()
+	for idx, action := range actions {
 		v := interface{}(action)
 		switch v.(type) {
 		case *process.KillProcessActionCommandSpec:
@@ -326,6 +344,7 @@ blade create cri process stop --process nginx --chaosblade-release /root/chaosbl
 blade create cri process stop --process-cmd java --chaosblade-release /root/chaosblade-0.6.0.tar.gz --container-id ee54f1e61c08`)
 
 		}
+		actions[idx] = NewNamespacedActionSpec(action, DefaultNamespaces...)
 	}
 	return commandModelSpec
 }
@@ -409,7 +428,7 @@ var ContainerNameFlag = &spec.ExpFlag{
 
 var ContainerLabelSelectorFlag = &spec.ExpFlag{
 	Name:                  "container-label-selector",
-	Desc:                  "Container label selector, when used with container-id or container-name, container-id or container-name is preferred",
+	Desc:                  "Container label selector, supports the kubernetes syntax, such as `app=web,tier notin (db),canary,!legacy`. When used with container-id or container-name, container-id or container-name is preferred. Specify container-multi-target to inject all the matched containers",
 	NoArgs:                false,
 	Required:              false,
 	RequiredWhenDestroyed: false,
@@ -449,14 +468,124 @@ var ChaosBladeOverrideFlag = &spec.ExpFlag{
 
 var ContainerRuntime = &spec.ExpFlag{
 	Name:     "container-runtime",
-	Desc:     "container runtime, support cri and containerd, default value is docker",
+	Desc:     "container runtime, support docker, containerd, crio, cri and podman, the runtime is detected automatically if omitted. cri talks to any runtime by the kubernetes CRI api",
 	NoArgs:   false,
 	Required: false,
 }
 
 var ContainerNamespace = &spec.ExpFlag{
 	Name:     "container-namespace",
-	Desc:     "container namespace, If container-runtime is containerd it will be used, default value is k8s.io",
+	Desc:     "container namespace, If container-runtime is containerd it will be used, all namespaces are searched for the container if omitted",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerImageFlag = &spec.ExpFlag{
+	Name:     "container-image",
+	Desc:     "Container image, the image reference such as redis:7, or the regular expression of the image with the regex: prefix such as `regex:.*/redis:7.*`. It works together with the other container flags",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerNamePatternFlag = &spec.ExpFlag{
+	Name:     "container-name-pattern",
+	Desc:     "Container name pattern, the glob pattern such as payment-*, or the regular expression with the regex: prefix such as regex:^payment-v[0-9]+$. It works together with the other container flags",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExcludeContainerLabelFlag = &spec.ExpFlag{
+	Name:     "exclude-container-label",
+	Desc:     "Exclude the containers whose labels match the label selector, such as track=canary",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExcludeContainerNameFlag = &spec.ExpFlag{
+	Name:     "exclude-container-name",
+	Desc:     "Exclude the containers whose names match any of the names or the name patterns, separated by commas, such as `web-1,canary-*`. A regex: pattern may contain commas, so it must be the last one, such as `web-1,regex:^(a{1,3}|b)$`",
+	NoArgs:   false,
+	Required: false,
+}
+
+var PodNamespaceFlag = &spec.ExpFlag{
+	Name:     "pod-namespace",
+	Desc:     "The kubernetes namespace of the pod, it is resolved by the io.kubernetes.pod.namespace label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var PodNameFlag = &spec.ExpFlag{
+	Name:     "pod-name",
+	Desc:     "The kubernetes pod name, it is resolved by the io.kubernetes.pod.name label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var K8sContainerNameFlag = &spec.ExpFlag{
+	Name:     "k8s-container-name",
+	Desc:     "The container name in the kubernetes pod, it is resolved by the io.kubernetes.container.name label of the containers",
+	NoArgs:   false,
+	Required: false,
+}
+
+var AllowPausedContainerFlag = &spec.ExpFlag{
+	Name:   "allow-paused-container",
+	Desc:   "Allow the experiment in the paused containers, the containers which are not running are refused by default",
+	NoArgs: true,
+}
+
+var LeaseFlag = &spec.ExpFlag{
+	Name:     "lease",
+	Desc:     "The lease of the experiment, such as 30s or 5m. The experiment is reverted automatically if the lease is not renewed in time by blade query cri renew --experiment-uid <uid>",
+	NoArgs:   false,
+	Required: false,
+}
+
+var NsFlag = &spec.ExpFlag{
+	Name:     "ns",
+	Desc:     "The namespaces of the container process which the experiment is executed in, separated by comma, such as pid,mnt,ipc. The supported namespaces are pid, mnt, net, ipc, uts, cgroup and user, the default namespaces are declared by the action",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExperimentUidFlag = &spec.ExpFlag{
+	Name:     "experiment-uid",
+	Desc:     "The uid of the cri experiment",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ExecModeFlag = &spec.ExpFlag{
+	Name:     "exec-mode",
+	Desc:     "The mode of executing the experiment, the values are nsexec, copy and sidecar, default value is nsexec. The copy mode copies the chaosblade tool into the container, the sidecar mode executes the experiment in a sidecar container which joins the network of the container",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerMultiTargetFlag = &spec.ExpFlag{
+	Name:   "container-multi-target",
+	Desc:   "Allow the container flags to match multiple containers, the experiment is applied to all of them, default value is false",
+	NoArgs: true,
+}
+
+var ContainerCountFlag = &spec.ExpFlag{
+	Name:     "container-count",
+	Desc:     "Randomly pick the number of the matched containers as the targets",
+	NoArgs:   false,
+	Required: false,
+}
+
+var ContainerPercentFlag = &spec.ExpFlag{
+	Name:     "container-percent",
+	Desc:     "Randomly pick the percentage of the matched containers as the targets, the value is in (0, 100]",
+	NoArgs:   false,
+	Required: false,
+}
+
+var SeedFlag = &spec.ExpFlag{
+	Name:     "seed",
+	Desc:     "The random seed of picking the containers by container-count or container-percent, the same seed picks the same containers",
 	NoArgs:   false,
 	Required: false,
 }
@@ -468,6 +597,18 @@ func GetContainerSelfFlags() []spec.ExpFlagSpec {
 		EndpointFlag,
 		ContainerRuntime,
 		ContainerNamespace,
+		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
 	}
 }
 
@@ -480,6 +621,20 @@ func GetExecSidecarFlags() []spec.ExpFlagSpec {
 		EndpointFlag,
 		ContainerRuntime,
 		ContainerNamespace,
+		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
+		LeaseFlag,
+		AllowPausedContainerFlag,
 	}
 }
 
@@ -494,6 +649,20 @@ func GetExecInContainerFlags() []spec.ExpFlagSpec {
 		ChaosBladeOverrideFlag,
 		ContainerRuntime,
 		ContainerNamespace,
+		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
+		LeaseFlag,
+		AllowPausedContainerFlag,
 	}
 }
 
@@ -505,14 +674,41 @@ func GetNSExecFlags() []spec.ExpFlagSpec {
 		ContainerRuntime,
 		ContainerNamespace,
 		ContainerLabelSelectorFlag,
+		ContainerImageFlag,
+		ContainerNamePatternFlag,
+		ExcludeContainerLabelFlag,
+		ExcludeContainerNameFlag,
+		PodNamespaceFlag,
+		PodNameFlag,
+		K8sContainerNameFlag,
+		ContainerMultiTargetFlag,
+		ContainerCountFlag,
+		ContainerPercentFlag,
+		SeedFlag,
+		LeaseFlag,
+		NsFlag,
+		ExecModeFlag,
+		AllowPausedContainerFlag,
 	}
 }
 
+// GetExecModeFlags returns the flags of the experiments whose execution mode is selected by the exec-mode flag
+func GetExecModeFlags() []spec.ExpFlagSpec {
+	flags := GetNSExecFlags()
+	return append(flags,
+		ImageRepoFlag,
+		ImageVersionFlag,
+		ChaosBladeReleaseFlag,
+		ChaosBladeOverrideFlag,
+	)
+}
+
 func getAllDockerFlags() []spec.ExpFlagSpec {
 	allFlags := make([]spec.ExpFlagSpec, 0)
 	allFlags = append(allFlags, GetContainerSelfFlags()...)
 	allFlags = append(allFlags, GetExecSidecarFlags()...)
 	allFlags = append(allFlags, GetExecInContainerFlags()...)
+	allFlags = append(allFlags, GetNSExecFlags()...)
 
 	set := make(map[spec.ExpFlagSpec]bool, 0)
 	flags := make([]spec.ExpFlagSpec, 0)
@@ -52,17 +52,19 @@ func NewCriExpModelSpec() *DockerExpModelSpec {
 		newFileCommandSpecForDocker(),
 		newProcessCommandModelSpecForDocker(),
 	}
-	spec.AddExecutorToModelSpec(NewCommonExecutor(), commonModelSpec...)
-	spec.AddFlagsToModelSpec(GetNSExecFlags, commonModelSpec...)
+	// the actions are executed in the namespaces declared by them
+	setExecModeExecutors(nil, commonModelSpec...)
+	spec.AddFlagsToModelSpec(GetExecModeFlags, commonModelSpec...)
 
 	// network
 	networkModeSpec := newNetworkCommandModelSpecForDocker()
-	spec.AddExecutorToModelSpec(NewNetworkExecutor(), networkModeSpec)
-	spec.AddFlagsToModelSpec(GetNSExecFlags, networkModeSpec)
+	setExecModeExecutors(NewNetWorkSidecarExecutor(), networkModeSpec)
+	spec.AddFlagsToModelSpec(GetExecModeFlags, networkModeSpec)
 
 	for _, action := range networkModeSpec.Actions() {
 		if action.Name() == "dns" || action.Name() == "occupy" {
-			action.SetExecutor(NewCommonExecutor())
+			// the hosts file and the occupying process are not kept by the sidecar which is removed after executing
+			setActionExecutor(action, nil)
 		}
 	}
 
@@ -97,17 +99,19 @@ func NewDockerExpModelSpec() *DockerExpModelSpec {
 		newProcessCommandModelSpecForDocker(),
 	}
 
-	spec.AddExecutorToModelSpec(NewCommonExecutor(), commonModelSpec...)
-	spec.AddFlagsToModelSpec(GetNSExecFlags, commonModelSpec...)
+	// the actions are executed in the namespaces declared by them
+	setExecModeExecutors(nil, commonModelSpec...)
+	spec.AddFlagsToModelSpec(GetExecModeFlags, commonModelSpec...)
 
 	// network
 	networkModeSpec := newNetworkCommandModelSpecForDocker()
-	spec.AddExecutorToModelSpec(NewNetworkExecutor(), networkModeSpec)
-	spec.AddFlagsToModelSpec(GetNSExecFlags, networkModeSpec)
+	setExecModeExecutors(NewNetWorkSidecarExecutor(), networkModeSpec)
+	spec.AddFlagsToModelSpec(GetExecModeFlags, networkModeSpec)
 
 	for _, action := range networkModeSpec.Actions() {
 		if action.Name() == "dns" || action.Name() == "occupy" {
-			action.SetExecutor(NewCommonExecutor())
+			// the hosts file and the occupying process are not kept by the sidecar which is removed after executing
+			setActionExecutor(action, nil)
 		}
 	}
 
@@ -126,3 +130,17 @@ func NewDockerExpModelSpec() *DockerExpModelSpec {
 	modelSpec.addExpModels(expModelCommandSpecs...)
 	return modelSpec
 }
+
+// setExecModeExecutors sets the executor of every action, the action is executed in the namespaces declared by it in
+// the nsexec mode, and the sidecar mode is supported if the sidecar executor is not nil
+func setExecModeExecutors(sidecar spec.Executor, expSpecs ...spec.ExpModelCommandSpec) {
+	for _, expSpec := range expSpecs {
+		for _, action := range expSpec.Actions() {
+			setActionExecutor(action, sidecar)
+		}
+	}
+}
+
+func setActionExecutor(action spec.ExpActionCommandSpec, sidecar spec.Executor) {
+	action.SetExecutor(NewExecModeExecutor(NewCommonExecutor(ActionNamespaces(action)...), sidecar))
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// nsexecNamespaces returns the namespaces of the nsexec executor of the action, false if it has no nsexec executor
+func nsexecNamespaces(action spec.ExpActionCommandSpec) ([]string, bool) {
+	modeExecutor, ok := action.Executor().(*ExecModeExecutor)
+	if !ok {
+		return nil, false
+	}
+	executor, ok := modeExecutor.executors[ExecModeNSExec].(*CommonExecutor)
+	if !ok {
+		return nil, false
+	}
+	return executor.Namespaces, true
+}
+
+func TestActionNamespaces(t *testing.T) {
+	modelSpec := NewCriExpModelSpec()
+	tests := []struct {
+		target   string
+		action   string
+		expected []string
+	}{
+		{target: "cpu", action: "fullload", expected: []string{"pid", "mnt"}},
+		{target: "mem", action: "load", expected: []string{"pid", "mnt"}},
+		{target: "network", action: "delay", expected: []string{"pid", "net"}},
+		{target: "network", action: "dns", expected: []string{"pid", "mnt"}},
+		{target: "network", action: "occupy", expected: []string{"pid", "mnt", "net"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.action, func(t *testing.T) {
+			action := modelSpec.GetExpActionModelSpec(tt.target, tt.action)
+			if action == nil {
+				t.Fatalf("the action %s %s is not found", tt.target, tt.action)
+			}
+			if namespaces := ActionNamespaces(action); !reflect.DeepEqual(namespaces, tt.expected) {
+				t.Errorf("ActionNamespaces() = %v, want %v", namespaces, tt.expected)
+			}
+			if namespaces, ok := nsexecNamespaces(action); !ok || !reflect.DeepEqual(namespaces, tt.expected) {
+				t.Errorf("the nsexec executor is executed in %v, want %v", namespaces, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNSExecActionsDeclareNamespaces(t *testing.T) {
+	for _, modelSpec := range []*DockerExpModelSpec{NewCriExpModelSpec(), NewDockerExpModelSpec()} {
+		for _, expModel := range modelSpec.ExpModels() {
+			for _, action := range expModel.Actions() {
+				if _, ok := nsexecNamespaces(action); !ok {
+					continue
+				}
+				// the actions must not fall back to the default namespaces silently
+				if _, ok := action.(NamespacedActionSpec); !ok {
+					t.Errorf("the action %s %s declares no namespace", expModel.Name(), action.Name())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-exec-os/exec/model"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// The namespaces of the container process which the experiments are executed in
+const (
+	NamespacePid    = "pid"
+	NamespaceMnt    = "mnt"
+	NamespaceNet    = "net"
+	NamespaceIpc    = "ipc"
+	NamespaceUts    = "uts"
+	NamespaceCgroup = "cgroup"
+	NamespaceUser   = "user"
+)
+
+// chaosOsNamespaceFlags are the namespaces joined by chaos_os through its nsexec channel
+var chaosOsNamespaceFlags = map[string]string{
+	NamespacePid: model.NsPidFlag.Name,
+	NamespaceMnt: model.NsMntFlag.Name,
+	NamespaceNet: model.NsNetFlag.Name,
+}
+
+// nsexecFlags are the flags of nsexec which join the namespaces
+var nsexecFlags = map[string]string{
+	NamespacePid:    "-p",
+	NamespaceMnt:    "-m",
+	NamespaceNet:    "-n",
+	NamespaceIpc:    "-i",
+	NamespaceUts:    "-u",
+	NamespaceCgroup: "-C",
+	NamespaceUser:   "-U",
+}
+
+// DefaultNamespaces are the namespaces of the actions which declare no namespace
+var DefaultNamespaces = []string{NamespacePid, NamespaceMnt}
+
+// NetworkNamespaces are the namespaces of the network actions which change the network of the container
+var NetworkNamespaces = []string{NamespacePid, NamespaceNet}
+
+// NamespacedActionSpec is the action which declares the namespaces of the container process it is executed in
+type NamespacedActionSpec interface {
+	spec.ExpActionCommandSpec
+	// Namespaces returns the namespaces of the action, they are overridden by the ns flag
+	Namespaces() []string
+}
+
+// namespacedActionSpec declares the namespaces of the action of chaosblade-exec-os
+type namespacedActionSpec struct {
+	spec.ExpActionCommandSpec
+	namespaces []string
+}
+
+// NewNamespacedActionSpec returns the action which is executed in the namespaces
+func NewNamespacedActionSpec(action spec.ExpActionCommandSpec, namespaces ...string) NamespacedActionSpec {
+	return &namespacedActionSpec{
+		ExpActionCommandSpec: action,
+		namespaces:           namespaces,
+	}
+}
+
+func (a *namespacedActionSpec) Namespaces() []string {
+	return a.namespaces
+}
+
+// ActionNamespaces returns the namespaces declared by the action, the DefaultNamespaces if it declares no namespace
+func ActionNamespaces(action spec.ExpActionCommandSpec) []string {
+	if namespaced, ok := action.(NamespacedActionSpec); ok && len(namespaced.Namespaces()) > 0 {
+		return namespaced.Namespaces()
+	}
+	return DefaultNamespaces
+}
+
+// namespaceOrder is the order of the namespaces in the arguments, the user namespace is joined first
+var namespaceOrder = []string{NamespaceUser, NamespaceIpc, NamespaceUts, NamespaceCgroup, NamespacePid, NamespaceMnt, NamespaceNet}
+
+// ParseNamespaces parses the namespaces separated by comma, such as pid,mnt,ipc
+func ParseNamespaces(value string) ([]string, error) {
+	namespaces := make([]string, 0)
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if _, ok := nsexecFlags[ns]; !ok {
+			return nil, fmt.Errorf("unsupported namespace `%s`, the supported namespaces are %s", ns, strings.Join(namespaceOrder, ", "))
+		}
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) == 0 {
+		return nil, fmt.Errorf("no namespace is specified")
+	}
+	return namespaces, nil
+}
+
+func hasNamespace(namespaces []string, namespace string) bool {
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// chaosOsNamespaceArgs returns the namespace flags of chaos_os, such as ns_pid and ns_mnt
+func chaosOsNamespaceArgs(namespaces []string) []string {
+	flags := make([]string, 0, len(namespaces))
+	for _, ns := range namespaceOrder {
+		if flag, ok := chaosOsNamespaceFlags[ns]; ok && hasNamespace(namespaces, ns) {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// nsexecNamespaceArgs returns the nsexec flags of the namespaces which chaos_os can not join by itself. The user
+// namespace is joined if it is declared, or if the current process has no privilege over the target process
+func nsexecNamespaceArgs(pid int32, namespaces []string) []string {
+	return nsexecArgsOf(pid, namespaces, func(ns string) bool {
+		_, ok := chaosOsNamespaceFlags[ns]
+		return !ok
+	})
+}
+
+// hangNamespaceArgs returns the nsexec flags of the namespaces which the hang process is started in. The mnt
+// namespace is joined by chaos_os itself, otherwise the chaos_os binary on the host can not be found
+func hangNamespaceArgs(pid int32, namespaces []string) []string {
+	return nsexecArgsOf(pid, namespaces, func(ns string) bool {
+		return ns != NamespaceMnt
+	})
+}
+
+// nsexecArgsOf returns the nsexec flags of the namespaces which are joined by nsexec in the order of namespaceOrder
+func nsexecArgsOf(pid int32, namespaces []string, joinedByNSExec func(ns string) bool) []string {
+	flags := make([]string, 0)
+	for _, ns := range namespaceOrder {
+		if !joinedByNSExec(ns) {
+			continue
+		}
+		if hasNamespace(namespaces, ns) || ns == NamespaceUser && needJoinUserNamespace(pid) {
+			flags = append(flags, nsexecFlags[ns])
+		}
+	}
+	return flags
+}
+
+// withNamespaces wraps the command by nsexec to join the namespaces which chaos_os can not join by itself first,
+// such as the user namespace, so that the namespaces owned by it can be joined later
+func withNamespaces(pid int32, namespaces []string, bin string, args []string) (string, []string) {
+	nsFlags := nsexecNamespaceArgs(pid, namespaces)
+	if len(nsFlags) == 0 {
+		return bin, args
+	}
+	nsexecBin := path.Join(util.GetProgramPath(), spec.BinPath, spec.NSExecBin)
+	nsexecArgs := NewArgs("-t", strconv.Itoa(int(pid))).Add(nsFlags...).Add("--", bin).Add(args...)
+	return nsexecBin, nsexecArgs.Argv()
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "single", value: "pid", expected: []string{"pid"}},
+		{name: "multiple", value: "pid,mnt,ipc", expected: []string{"pid", "mnt", "ipc"}},
+		{name: "spaces and empty items", value: " pid , ,net,", expected: []string{"pid", "net"}},
+		{name: "all", value: "pid,mnt,net,ipc,uts,cgroup,user", expected: []string{"pid", "mnt", "net", "ipc", "uts", "cgroup", "user"}},
+		{name: "unsupported", value: "pid,time", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+		{name: "only separators", value: ", ,", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespaces, err := ParseNamespaces(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(namespaces, tt.expected) {
+				t.Errorf("got %v, want %v", namespaces, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNamespaceArgs(t *testing.T) {
+	// the process is in its own user namespace, so the user namespace is joined only if it is declared
+	pid := int32(os.Getpid())
+	tests := []struct {
+		name       string
+		namespaces []string
+		chaosOs    []string
+		nsexec     []string
+		hang       []string
+	}{
+		{
+			name:       "pid and mnt",
+			namespaces: []string{"mnt", "pid"},
+			chaosOs:    []string{"ns_pid", "ns_mnt"},
+			nsexec:     []string{},
+			hang:       []string{"-p"},
+		},
+		{
+			name:       "pid and net",
+			namespaces: []string{"pid", "net"},
+			chaosOs:    []string{"ns_pid", "ns_net"},
+			nsexec:     []string{},
+			hang:       []string{"-p", "-n"},
+		},
+		{
+			name:       "joined by nsexec",
+			namespaces: []string{"pid", "mnt", "uts", "ipc", "user", "cgroup"},
+			chaosOs:    []string{"ns_pid", "ns_mnt"},
+			nsexec:     []string{"-U", "-i", "-u", "-C"},
+			hang:       []string{"-U", "-i", "-u", "-C", "-p"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if args := chaosOsNamespaceArgs(tt.namespaces); !reflect.DeepEqual(args, tt.chaosOs) {
+				t.Errorf("chaos_os args: got %v, want %v", args, tt.chaosOs)
+			}
+			if args := nsexecNamespaceArgs(pid, tt.namespaces); !reflect.DeepEqual(args, tt.nsexec) {
+				t.Errorf("nsexec args: got %v, want %v", args, tt.nsexec)
+			}
+			if args := hangNamespaceArgs(pid, tt.namespaces); !reflect.DeepEqual(args, tt.hang) {
+				t.Errorf("hang args: got %v, want %v", args, tt.hang)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+const (
+	// DefaultPolicyFile is the policy file of the cri experiments, there is no restriction if it does not exist
+	DefaultPolicyFile = "/etc/chaosblade/cri-policy.yaml"
+	// PolicyFileEnv overrides the path of the policy file
+	PolicyFileEnv = "CHAOSBLADE_CRI_POLICY"
+)
+
+// Policy restricts the containers which the experiments can be injected into, for example:
+//
+//	protectedLabelSelectors:
+//	  - component in (kube-apiserver,etcd)
+//	protectedNamespaces:
+//	  - kube-system
+//	protectedImages:
+//	  - regex:.*/pause:.*
+//	maxConcurrentTargets: 10
+type Policy struct {
+	// ProtectedLabelSelectors protect the containers whose labels match any of the selectors
+	ProtectedLabelSelectors []string `yaml:"protectedLabelSelectors"`
+	// ProtectedNamespaces protect the containers in the kubernetes namespaces or the containerd namespaces
+	ProtectedNamespaces []string `yaml:"protectedNamespaces"`
+	// ProtectedImages protect the containers whose images match any of the image references or the regular expressions
+	// with the regex: prefix
+	ProtectedImages []string `yaml:"protectedImages"`
+	// ProtectedNames protect the containers whose names match any of the names or the name patterns
+	ProtectedNames []string `yaml:"protectedNames"`
+	// MaxConcurrentTargets is the max number of the containers which are injected by the active experiments
+	// on the node, there is no limit if it is zero
+	MaxConcurrentTargets int `yaml:"maxConcurrentTargets"`
+	// AllowSandbox allows the experiments in the pause containers of the pods, which are protected by default
+	AllowSandbox bool `yaml:"allowSandbox"`
+
+	file      string
+	selectors []container.LabelSelector
+}
+
+// policyFile returns the path of the policy file
+func policyFile() string {
+	if file := os.Getenv(PolicyFileEnv); file != "" {
+		return file
+	}
+	return DefaultPolicyFile
+}
+
+// LoadPolicy returns the policy in the file, nil if the file does not exist
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	policy := &Policy{file: file}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, err
+	}
+	for _, raw := range policy.ProtectedLabelSelectors {
+		selector, err := container.ParseLabelSelector(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protected label selector `%s`, %v", raw, err)
+		}
+		policy.selectors = append(policy.selectors, selector)
+	}
+	for _, image := range policy.ProtectedImages {
+		if err := container.ValidateImagePattern(image); err != nil {
+			return nil, fmt.Errorf("invalid protected image `%s`, %v", image, err)
+		}
+	}
+	for _, name := range policy.ProtectedNames {
+		if err := container.ValidateNamePattern(name); err != nil {
+			return nil, fmt.Errorf("invalid protected name `%s`, %v", name, err)
+		}
+	}
+	return policy, nil
+}
+
+// CheckContainer returns the reason if the container is protected by the policy, or empty if it is allowed
+func (p *Policy) CheckContainer(info container.ContainerInfo) string {
+	if !p.AllowSandbox && info.IsSandbox() {
+		return fmt.Sprintf("the container %s is a pod sandbox", info.ContainerId)
+	}
+	for idx, selector := range p.selectors {
+		if len(selector) > 0 && selector.Matches(info.Labels) {
+			return fmt.Sprintf("the container %s matches the protected label selector `%s`", info.ContainerId,
+				p.ProtectedLabelSelectors[idx])
+		}
+	}
+	for _, namespace := range p.ProtectedNamespaces {
+		if info.Labels[container.KubernetesPodNamespaceLabel] == namespace || info.Namespace == namespace {
+			return fmt.Sprintf("the container %s is in the protected namespace `%s`", info.ContainerId, namespace)
+		}
+	}
+	for _, image := range p.ProtectedImages {
+		if container.MatchImage(image, info.Image) {
+			return fmt.Sprintf("the image %s of the container %s is protected by `%s`", info.Image, info.ContainerId, image)
+		}
+	}
+	if container.MatchAnyName(p.ProtectedNames, info.ContainerName) {
+		return fmt.Sprintf("the container %s is protected by the name", info.ContainerName)
+	}
+	return ""
+}
+
+// CheckTargets returns the reason if the number of the targets exceeds the max concurrent targets, the targets of
+// the experiment itself are not counted twice. It is called with the state directory locked
+func (p *Policy) CheckTargets(uid string, count int) string {
+	if p.MaxConcurrentTargets <= 0 {
+		return ""
+	}
+	active := countActiveTargets(uid)
+	if active+count > p.MaxConcurrentTargets {
+		return fmt.Sprintf("%d containers are injected by the active experiments, %d more exceed the max concurrent targets %d",
+			active, count, p.MaxConcurrentTargets)
+	}
+	return ""
+}
+
+// enforcePolicy returns the failed response if the policy denies the experiment in the containers.
+// Destroying is never denied, so the experiments can always be reverted
+func enforcePolicy(ctx context.Context, uid string, containers []container.ContainerInfo) *spec.Response {
+	if _, ok := spec.IsDestroy(ctx); ok {
+		return nil
+	}
+	file := policyFile()
+	policy, err := LoadPolicy(file)
+	if err != nil {
+		// deny all the experiments if the policy is broken
+		reason := fmt.Sprintf("load the policy failed, %v", err)
+		log.Errorf(ctx, "%s", ExperimentDenied.Sprintf(file, reason))
+		return spec.ResponseFailWithFlags(ExperimentDenied, file, reason)
+	}
+	if policy == nil {
+		return nil
+	}
+	for _, ctr := range containers {
+		if reason := policy.CheckContainer(ctr); reason != "" {
+			log.Errorf(ctx, "%s", ExperimentDenied.Sprintf(file, reason))
+			return spec.ResponseFailWithFlags(ExperimentDenied, file, reason)
+		}
+	}
+	if policy.MaxConcurrentTargets <= 0 {
+		return nil
+	}
+	// check and reserve the targets with the state directory locked, so the concurrent experiments can not exceed
+	// the max concurrent targets together. The reservation is released by FanOut after the state is saved
+	var reason string
+	err = withStateLock(func() error {
+		if reason = policy.CheckTargets(uid, len(containers)); reason != "" {
+			return nil
+		}
+		return saveReservation(uid, len(containers))
+	})
+	if err != nil {
+		reason = fmt.Sprintf("reserve the targets failed, %v", err)
+	}
+	if reason != "" {
+		log.Errorf(ctx, "%s", ExperimentDenied.Sprintf(file, reason))
+		return spec.ResponseFailWithFlags(ExperimentDenied, file, reason)
+	}
+	return nil
+}
+
+// ReservationTTL is how long the targets reserved by the policy are counted, the reservation is released when the
+// experiment is injected or fails. It expires if the process exits before releasing it, such as a crash
+var ReservationTTL = 10 * time.Minute
+
+// reservation reserves the targets of the experiment which is being injected. It is saved beside the experiment
+// states but it is not a state, so it is not shown by status or destroyed by destroy-all
+type reservation struct {
+	Uid        string    `json:"uid"`
+	Count      int       `json:"count"`
+	ExpireTime time.Time `json:"expireTime"`
+}
+
+func reservationFile(uid string) string {
+	return filepath.Join(stateDir(), uid+".reserved")
+}
+
+// saveReservation reserves the targets for the experiment, it is called with the state directory locked
+func saveReservation(uid string, count int) error {
+	file := reservationFile(uid)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(reservation{Uid: uid, Count: count, ExpireTime: time.Now().Add(ReservationTTL)})
+	if err != nil {
+		return err
+	}
+	tmpFile := file + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// listReservations returns the reservations which are not expired, the expired ones are removed.
+// It is called with the state directory locked
+func listReservations() []reservation {
+	files, err := filepath.Glob(filepath.Join(stateDir(), "*.reserved"))
+	if err != nil {
+		return nil
+	}
+	reservations := make([]reservation, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var reserved reservation
+		if err := json.Unmarshal(data, &reserved); err != nil || time.Now().After(reserved.ExpireTime) {
+			os.Remove(file)
+			continue
+		}
+		reservations = append(reservations, reserved)
+	}
+	return reservations
+}
+
+// releaseReservation removes the reservation of the experiment, it does nothing if there is no reservation
+func releaseReservation(ctx context.Context, uid string) {
+	err := withStateLock(func() error {
+		if err := os.Remove(reservationFile(uid)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warnf(ctx, "release the reserved targets of %s failed, %v", uid, err)
+	}
+}
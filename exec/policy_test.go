@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// usePolicy writes the policy file which limits the concurrent targets
+func usePolicy(t *testing.T, maxConcurrentTargets int) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	data := []byte("maxConcurrentTargets: " + strconv.Itoa(maxConcurrentTargets) + "\n")
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(PolicyFileEnv, file)
+}
+
+func TestEnforcePolicyReservation(t *testing.T) {
+	useTempStateDir(t)
+	usePolicy(t, 3)
+	containers := newSampleContainers(2)
+	ctx := context.Background()
+
+	if response := enforcePolicy(ctx, "uid-a", containers); response != nil {
+		t.Fatalf("enforcePolicy(uid-a) = %s", response.Err)
+	}
+	if response := enforcePolicy(ctx, "uid-b", containers); response == nil || response.Code != ExperimentDenied.Code {
+		t.Fatalf("enforcePolicy(uid-b) = %v, want denied by the targets reserved by uid-a", response)
+	}
+	// the reservation is not an experiment, it is not shown by status or destroyed by destroy-all
+	states, err := ListExperimentStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 0 {
+		t.Errorf("ListExperimentStates() = %d states, want none for the reservation", len(states))
+	}
+
+	releaseReservation(ctx, "uid-a")
+	if response := enforcePolicy(ctx, "uid-b", containers); response != nil {
+		t.Errorf("enforcePolicy(uid-b) = %s after the reservation is released", response.Err)
+	}
+}
+
+func TestReservationExpires(t *testing.T) {
+	useTempStateDir(t)
+	ttl := ReservationTTL
+	ReservationTTL = -time.Second
+	t.Cleanup(func() { ReservationTTL = ttl })
+	if err := withStateLock(func() error { return saveReservation("uid-crashed", 5) }); err != nil {
+		t.Fatal(err)
+	}
+	if count := countActiveTargets("uid-other"); count != 0 {
+		t.Errorf("countActiveTargets() = %d, want the expired reservation not counted", count)
+	}
+	if _, err := os.Stat(reservationFile("uid-crashed")); !os.IsNotExist(err) {
+		t.Errorf("the expired reservation is not removed, %v", err)
+	}
+}
+
+func TestFanOutReleasesReservation(t *testing.T) {
+	useTempStateDir(t)
+	usePolicy(t, 5)
+	containers := newSampleContainers(2)
+	client := &ExperimentClient{Container: &fakeContainer{containers: containers}}
+	executor := &BaseClientExecutor{Kind: ExecutorKindNSExec}
+	inject := func(ctx context.Context, info container.ContainerInfo) *spec.Response {
+		return spec.ReturnSuccess(info.ContainerId)
+	}
+	tests := []struct {
+		name      string
+		flags     map[string]string
+		wantCount int
+	}{
+		{"injected", map[string]string{}, 2},
+		{"invalid lease", map[string]string{LeaseFlag.Name: "-1s"}, 0},
+	}
+	for idx, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := "uid-fanout-" + strconv.Itoa(idx)
+			ctx := context.Background()
+			if response := enforcePolicy(ctx, uid, containers); response != nil {
+				t.Fatalf("enforcePolicy() = %s", response.Err)
+			}
+			expModel := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: tt.flags}
+			executor.FanOut(ctx, client, uid, expModel, containers, inject)
+			if _, err := os.Stat(reservationFile(uid)); !os.IsNotExist(err) {
+				t.Errorf("the reservation is not released, %v", err)
+			}
+			var count int
+			withStateLock(func() error {
+				count = countActiveTargets("uid-other")
+				return nil
+			})
+			if count != tt.wantCount {
+				t.Errorf("countActiveTargets() = %d, want %d", count, tt.wantCount)
+			}
+			RemoveExperimentState(uid)
+		})
+	}
+}
+
+func TestListExperimentStatesSkipsLegacyReservation(t *testing.T) {
+	useTempStateDir(t)
+	legacy := &ExperimentState{Uid: "uid-legacy", Containers: []TargetContainer{{ContainerId: "abc"}}}
+	if err := SaveExperimentState(legacy); err != nil {
+		t.Fatal(err)
+	}
+	states, err := ListExperimentStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 0 {
+		t.Errorf("ListExperimentStates() = %v, want the legacy reservation skipped", states)
+	}
+}
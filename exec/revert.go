@@ -0,0 +1,264 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// TimeoutFlag is the flag of blade which reverts the experiment after the seconds
+const TimeoutFlag = "timeout"
+
+// RevertCommand returns the command which reverts the experiment when its lease expires,
+// it is the same as destroying the experiment manually
+var RevertCommand = func(uid string) []string {
+	return []string{path.Join(util.GetProgramPath(), "blade"), spec.Destroy, uid}
+}
+
+// ScheduledRevert is the automatic revert of the experiment
+type ScheduledRevert struct {
+	// Lease is renewed by the heartbeat, the experiment is reverted if it is not renewed in time
+	Lease string `json:"lease,omitempty"`
+	// Deadline is the time specified by the timeout flag, the lease is never renewed after it.
+	// It is nil if the timeout flag is not specified
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// RevertTime is the time when the experiment is reverted
+	RevertTime time.Time `json:"revertTime"`
+	// WatcherPid is the pid of the detached watcher which reverts the experiment
+	WatcherPid int `json:"watcherPid,omitempty"`
+}
+
+// revertFile contains the unix time when the experiment is reverted, the watcher reads it every second
+func revertFile(uid string) string {
+	return filepath.Join(stateDir(), uid+".revert")
+}
+
+// parseRevert returns the automatic revert of the experiment by the lease flag, and by the timeout flag for the
+// nsexec executors whose chaos_os commands do not handle the timeout. It is nil if neither is specified
+func parseRevert(kind string, expModel *spec.ExpModel) (*ScheduledRevert, string, error) {
+	now := time.Now()
+	revert := &ScheduledRevert{}
+	if timeout := expModel.ActionFlags[TimeoutFlag]; timeout != "" && kind == ExecutorKindNSExec {
+		seconds, err := strconv.ParseInt(timeout, 10, 64)
+		if err != nil {
+			return nil, TimeoutFlag, err
+		}
+		if seconds <= 0 {
+			return nil, TimeoutFlag, fmt.Errorf("the timeout must be positive")
+		}
+		deadline := now.Add(time.Duration(seconds) * time.Second)
+		revert.Deadline = &deadline
+		revert.RevertTime = deadline
+	}
+	if lease := expModel.ActionFlags[LeaseFlag.Name]; lease != "" {
+		duration, err := time.ParseDuration(lease)
+		if err != nil {
+			return nil, LeaseFlag.Name, err
+		}
+		if duration <= 0 {
+			return nil, LeaseFlag.Name, fmt.Errorf("the lease must be positive")
+		}
+		revert.Lease = lease
+		revert.RevertTime = revert.leaseRevertTime(now, duration)
+	}
+	if revert.RevertTime.IsZero() {
+		return nil, "", nil
+	}
+	return revert, "", nil
+}
+
+// leaseRevertTime returns the revert time when the lease is renewed, which never exceeds the deadline
+func (r *ScheduledRevert) leaseRevertTime(now time.Time, lease time.Duration) time.Time {
+	revertTime := now.Add(lease)
+	if r.Deadline != nil && r.Deadline.Before(revertTime) {
+		return *r.Deadline
+	}
+	return revertTime
+}
+
+func writeRevertTime(uid string, revertTime time.Time) error {
+	file := revertFile(uid)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmpFile := file + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(strconv.FormatInt(revertTime.Unix(), 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// scheduleRevert starts the watcher which reverts the experiment at the revert time, and records it in the state
+func scheduleRevert(state *ExperimentState, revert *ScheduledRevert) error {
+	if err := writeRevertTime(state.Uid, revert.RevertTime); err != nil {
+		return err
+	}
+	pid, err := startRevertWatcher(state.Uid, revertFile(state.Uid))
+	if err != nil {
+		os.Remove(revertFile(state.Uid))
+		return err
+	}
+	revert.WatcherPid = pid
+	state.Revert = revert
+	_, err = UpdateExperimentState(state.Uid, func(current *ExperimentState, ok bool) (*ExperimentState, error) {
+		if !ok {
+			return state, nil
+		}
+		current.Revert = revert
+		return current, nil
+	})
+	return err
+}
+
+// cancelRevert stops the watcher of the experiment. The watcher is not killed if the revert file is removed,
+// because it is reverting the experiment by itself
+func cancelRevert(ctx context.Context, uid string, revert *ScheduledRevert) {
+	if revert == nil {
+		return
+	}
+	if err := os.Remove(revertFile(uid)); err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf(ctx, "remove the revert file of %s failed, %v", uid, err)
+		}
+		return
+	}
+	if _, err := killExperimentProcess(revert.WatcherPid, uid); err != nil {
+		log.Warnf(ctx, "kill the revert watcher %d of %s failed, %v", revert.WatcherPid, uid, err)
+	}
+}
+
+// RenewLease extends the lease of the experiment by the heartbeat and returns the new revert time.
+// The watcher is restarted if it is not running
+func RenewLease(uid string) (time.Time, error) {
+	var revertTime time.Time
+	restart := false
+	state, err := UpdateExperimentState(uid, func(state *ExperimentState, ok bool) (*ExperimentState, error) {
+		if !ok {
+			return nil, fmt.Errorf("the experiment is not active")
+		}
+		if state.Revert == nil || state.Revert.Lease == "" {
+			return nil, fmt.Errorf("the experiment has no lease")
+		}
+		duration, err := time.ParseDuration(state.Revert.Lease)
+		if err != nil {
+			return nil, err
+		}
+		revert := *state.Revert
+		revert.RevertTime = revert.leaseRevertTime(time.Now(), duration)
+		revertTime = revert.RevertTime
+		if restart = !isExperimentProcessAlive(revert.WatcherPid, uid); !restart {
+			if err := writeRevertTime(uid, revert.RevertTime); err != nil {
+				return nil, err
+			}
+		}
+		state.Revert = &revert
+		return state, nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if restart {
+		return revertTime, scheduleRevert(state, state.Revert)
+	}
+	return revertTime, nil
+}
+
+// RenewExperimentLease is the heartbeat of the experiment, it returns the response which contains the revert time.
+// The revert watchers of the other experiments which are not running are resumed too
+func RenewExperimentLease(ctx context.Context, uid string) *spec.Response {
+	if uid == "" {
+		log.Errorf(ctx, "%s", spec.ParameterLess.Sprintf(ExperimentUidFlag.Name))
+		return spec.ResponseFailWithFlags(spec.ParameterLess, ExperimentUidFlag.Name)
+	}
+	revertTime, err := RenewLease(uid)
+	if err != nil {
+		log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(LeaseFlag.Name, uid, err))
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, LeaseFlag.Name, uid, err)
+	}
+	resumeRevertWatchers(ctx)
+	return spec.ReturnSuccess(&ExperimentResult{RevertTime: &revertTime})
+}
+
+// ResumeRevertWatchers restarts the watchers which are not running, such as after the node is rebooted or the watcher
+// crashed. The experiments whose revert time has passed are reverted immediately by the watchers.
+// The experiment whose revert file is removed is skipped, because it is being destroyed manually or by its watcher
+func ResumeRevertWatchers(ctx context.Context) error {
+	states, err := ListExperimentStates()
+	if err != nil {
+		return err
+	}
+	failures := make([]string, 0)
+	for _, state := range states {
+		if state.Revert == nil || isExperimentProcessAlive(state.Revert.WatcherPid, state.Uid) {
+			continue
+		}
+		if _, err := os.Stat(revertFile(state.Uid)); err != nil {
+			if !os.IsNotExist(err) {
+				failures = append(failures, fmt.Sprintf("%s: %v", state.Uid, err))
+			}
+			continue
+		}
+		log.Infof(ctx, "resume the revert watcher of %s, revert time: %s", state.Uid, state.Revert.RevertTime)
+		if err := scheduleRevert(state, state.Revert); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", state.Uid, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("resume the revert watchers failed, %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// ResumeExperimentWatchers resumes the revert watchers which are not running, it is invoked after the node is
+// rebooted, so the experiments whose lease expired are reverted without creating a new experiment
+func ResumeExperimentWatchers(ctx context.Context) *spec.Response {
+	if err := ResumeRevertWatchers(ctx); err != nil {
+		log.Errorf(ctx, "%s", spec.ContainerExecFailed.Sprintf("ResumeRevertWatchers", err))
+		return spec.ResponseFailWithFlags(spec.ContainerExecFailed, "ResumeRevertWatchers", err)
+	}
+	return spec.ReturnSuccess("resumed")
+}
+
+// resumeRevertWatchers resumes the revert watchers by the commands which manage the experiments,
+// the failure is only logged because it does not fail the command
+func resumeRevertWatchers(ctx context.Context) {
+	if err := ResumeRevertWatchers(ctx); err != nil {
+		log.Warnf(ctx, "%v", err)
+	}
+}
+
+func withRevert(revert *ScheduledRevert, response *spec.Response) *spec.Response {
+	if revert == nil {
+		return response
+	}
+	return withExperimentResult(response, func(result *ExperimentResult) {
+		revertTime := revert.RevertTime
+		result.RevertTime = &revertTime
+		result.Lease = revert.Lease
+	})
+}
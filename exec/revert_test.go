@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestParseRevert(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		flags    map[string]string
+		wantNil  bool
+		flag     string
+		lease    string
+		deadline bool
+		within   time.Duration
+	}{
+		{name: "none", kind: ExecutorKindNSExec, flags: map[string]string{}, wantNil: true},
+		{name: "timeout of the copy executor", kind: ExecutorKindCopy, flags: map[string]string{TimeoutFlag: "60"}, wantNil: true},
+		{name: "timeout", kind: ExecutorKindNSExec, flags: map[string]string{TimeoutFlag: "60"}, deadline: true, within: time.Minute},
+		{name: "lease", kind: ExecutorKindNSExec, flags: map[string]string{LeaseFlag.Name: "30s"}, lease: "30s", within: 30 * time.Second},
+		{name: "lease within timeout", kind: ExecutorKindNSExec, flags: map[string]string{TimeoutFlag: "10", LeaseFlag.Name: "5m"}, lease: "5m", deadline: true, within: 10 * time.Second},
+		{name: "invalid timeout", kind: ExecutorKindNSExec, flags: map[string]string{TimeoutFlag: "1m"}, wantNil: true, flag: TimeoutFlag},
+		{name: "negative timeout", kind: ExecutorKindNSExec, flags: map[string]string{TimeoutFlag: "-1"}, wantNil: true, flag: TimeoutFlag},
+		{name: "invalid lease", kind: ExecutorKindNSExec, flags: map[string]string{LeaseFlag.Name: "30"}, wantNil: true, flag: LeaseFlag.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			revert, flag, err := parseRevert(tt.kind, &spec.ExpModel{ActionFlags: tt.flags})
+			if flag != tt.flag || (err != nil) != (tt.flag != "") {
+				t.Fatalf("parseRevert() flag = %q, err = %v, want flag %q", flag, err, tt.flag)
+			}
+			if (revert == nil) != tt.wantNil {
+				t.Fatalf("parseRevert() = %+v, want nil %v", revert, tt.wantNil)
+			}
+			if revert == nil {
+				return
+			}
+			if revert.Lease != tt.lease {
+				t.Errorf("the lease = %q, want %q", revert.Lease, tt.lease)
+			}
+			if (revert.Deadline != nil) != tt.deadline {
+				t.Errorf("the deadline = %v, want deadline %v", revert.Deadline, tt.deadline)
+			}
+			if until := revert.RevertTime.Sub(now); until <= 0 || until > tt.within+time.Second {
+				t.Errorf("the revert time is %s later, want within %s", until, tt.within)
+			}
+		})
+	}
+}
+
+func TestLeaseRevertTime(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(time.Minute)
+	tests := []struct {
+		name     string
+		deadline *time.Time
+		lease    time.Duration
+		want     time.Time
+	}{
+		{name: "without deadline", lease: time.Hour, want: now.Add(time.Hour)},
+		{name: "before deadline", deadline: &deadline, lease: 30 * time.Second, want: now.Add(30 * time.Second)},
+		{name: "after deadline", deadline: &deadline, lease: time.Hour, want: deadline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert := &ScheduledRevert{Deadline: tt.deadline}
+			if got := revert.leaseRevertTime(now, tt.lease); !got.Equal(tt.want) {
+				t.Errorf("leaseRevertTime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduledRevertOmitsDeadline(t *testing.T) {
+	data, err := json.Marshal(&ScheduledRevert{Lease: "30s", RevertTime: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "deadline") {
+		t.Errorf("the deadline is not omitted, got %s", data)
+	}
+}
+
+func TestResumeRevertWatchersSkipsDestroyingExperiment(t *testing.T) {
+	useTempStateDir(t)
+	state := &ExperimentState{
+		Uid:        "uid-destroying",
+		Target:     "network",
+		Action:     "delay",
+		Containers: []TargetContainer{{ContainerId: "abc"}},
+		Revert:     &ScheduledRevert{Lease: "30s", RevertTime: time.Now().Add(-time.Second)},
+	}
+	if err := SaveExperimentState(state); err != nil {
+		t.Fatal(err)
+	}
+	// the revert file is removed when destroying, so the watcher must not be started again
+	if err := ResumeRevertWatchers(context.Background()); err != nil {
+		t.Fatalf("ResumeRevertWatchers() = %v", err)
+	}
+	current, ok, err := LoadExperimentState(state.Uid)
+	if err != nil || !ok {
+		t.Fatalf("LoadExperimentState() = %v, %v", ok, err)
+	}
+	if current.Revert.WatcherPid != 0 {
+		t.Errorf("the watcher of the destroying experiment is resumed, pid %d", current.Revert.WatcherPid)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// revertWatcherScript waits until the revert time in the file, then reverts the experiment by the revert command.
+// The file is removed before reverting, so the watcher is never killed by the destroy which it invokes
+const revertWatcherScript = `file="$1"; uid="$2"; shift 2
+while [ -f "$file" ]; do
+	deadline=$(cat "$file" 2>/dev/null)
+	now=$(date +%s)
+	if [ -n "$deadline" ] && [ "$now" -ge "$deadline" ]; then
+		rm -f "$file"
+		exec "$@"
+	fi
+	sleep 1
+done`
+
+// startRevertWatcher starts the detached watcher of the experiment which survives the blade process exiting
+func startRevertWatcher(uid, file string) (int, error) {
+	args := append([]string{"-c", revertWatcherScript, "revert-watcher", file, uid}, RevertCommand(uid)...)
+	command := exec.Command("/bin/sh", args...)
+	command.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer devNull.Close()
+	command.Stdin = devNull
+	command.Stdout = devNull
+	command.Stderr = devNull
+	if err := command.Start(); err != nil {
+		return 0, err
+	}
+	// reap the watcher if it exits before the blade process, otherwise it is reparented to init
+	go func() {
+		_ = command.Wait()
+	}()
+	return command.Process.Pid, nil
+}
@@ -0,0 +1,137 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// useRevertCommand replaces the revert command of the watchers, so the watchers started by the test do nothing
+func useRevertCommand(t *testing.T) {
+	t.Helper()
+	command := RevertCommand
+	RevertCommand = func(uid string) []string { return []string{"/bin/true", uid} }
+	t.Cleanup(func() { RevertCommand = command })
+}
+
+// stopRevertWatcher stops the watcher by removing its revert file, and kills it if it is still running
+func stopRevertWatcher(t *testing.T, uid string) {
+	t.Helper()
+	t.Cleanup(func() {
+		state, ok, _ := LoadExperimentState(uid)
+		os.Remove(revertFile(uid))
+		if ok && state.Revert != nil {
+			killExperimentProcess(state.Revert.WatcherPid, uid)
+		}
+	})
+}
+
+func TestResumeRevertWatchers(t *testing.T) {
+	useTempStateDir(t)
+	useRevertCommand(t)
+	uid := "uid-resumed"
+	stopRevertWatcher(t, uid)
+	revertTime := time.Now().Add(time.Hour)
+	state := &ExperimentState{
+		Uid:        uid,
+		Target:     "network",
+		Action:     "delay",
+		Containers: []TargetContainer{{ContainerId: "abc"}},
+		// the watcher is not running after the node is rebooted
+		Revert: &ScheduledRevert{Lease: "30s", RevertTime: revertTime, WatcherPid: 0},
+	}
+	if err := SaveExperimentState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeRevertTime(uid, revertTime); err != nil {
+		t.Fatal(err)
+	}
+	if response := QueryExperiments(context.Background(), uid); !response.Success {
+		t.Fatalf("QueryExperiments() = %+v", response)
+	}
+	current, ok, err := LoadExperimentState(uid)
+	if err != nil || !ok {
+		t.Fatalf("LoadExperimentState() = %v, %v", ok, err)
+	}
+	if !isExperimentProcessAlive(current.Revert.WatcherPid, uid) {
+		t.Errorf("the watcher is not resumed by status, pid %d", current.Revert.WatcherPid)
+	}
+}
+
+func TestRenewLease(t *testing.T) {
+	useTempStateDir(t)
+	useRevertCommand(t)
+	deadline := time.Now().Add(10 * time.Second)
+	tests := []struct {
+		name   string
+		revert *ScheduledRevert
+		want   func(now time.Time) time.Time
+		err    bool
+	}{
+		{name: "without lease", err: true},
+		{
+			name:   "lease",
+			revert: &ScheduledRevert{Lease: "1m", RevertTime: time.Now()},
+			want:   func(now time.Time) time.Time { return now.Add(time.Minute) },
+		},
+		{
+			name:   "lease capped by deadline",
+			revert: &ScheduledRevert{Lease: "1m", Deadline: &deadline, RevertTime: time.Now()},
+			want:   func(now time.Time) time.Time { return deadline },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := "uid-" + tt.name
+			stopRevertWatcher(t, uid)
+			state := &ExperimentState{
+				Uid:        uid,
+				Target:     "network",
+				Action:     "delay",
+				Containers: []TargetContainer{{ContainerId: "abc"}},
+				Revert:     tt.revert,
+			}
+			if err := SaveExperimentState(state); err != nil {
+				t.Fatal(err)
+			}
+			now := time.Now()
+			revertTime, err := RenewLease(uid)
+			if (err != nil) != tt.err {
+				t.Fatalf("RenewLease() error = %v, want error %v", err, tt.err)
+			}
+			if tt.err {
+				return
+			}
+			if want := tt.want(now); revertTime.Sub(want).Abs() > time.Second {
+				t.Errorf("RenewLease() = %s, want %s", revertTime, want)
+			}
+			current, _, err := LoadExperimentState(uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the watcher is restarted because it is not running
+			if !isExperimentProcessAlive(current.Revert.WatcherPid, uid) {
+				t.Errorf("the watcher is not restarted, pid %d", current.Revert.WatcherPid)
+			}
+		})
+	}
+}
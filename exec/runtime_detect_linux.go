@@ -0,0 +1,262 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	versionservice "github.com/containerd/containerd/api/services/version/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gopkg.in/yaml.v2"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/containerd"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/cri"
+)
+
+// CrictlConfigFile is the config file of crictl which contains the runtime endpoint of the node
+const CrictlConfigFile = "/etc/crictl.yaml"
+
+// runtimeSockets are the well-known sockets of the container runtimes, probed in order. Docker is probed before
+// containerd, because the containers of docker are also found in the moby namespace of containerd
+var runtimeSockets = []RuntimeDetection{
+	{Runtime: container.DockerRuntime, Endpoint: "unix:///var/run/docker.sock"},
+	{Runtime: container.ContainerdRuntime, Endpoint: "unix:///run/containerd/containerd.sock"},
+	{Runtime: container.CrioRuntime, Endpoint: "unix:///var/run/crio/crio.sock"},
+}
+
+// detectClient returns the client of the first runtime which knows the target container. The candidates come from
+// the cri-endpoint flag, CONTAINER_RUNTIME_ENDPOINT, /etc/crictl.yaml and the well-known sockets
+func detectClient(ctx context.Context, expModel *spec.ExpModel) (container.Container, *RuntimeDetection, error) {
+	candidates := runtimeCandidates(ctx, expModel.ActionFlags[EndpointFlag.Name])
+	if len(candidates) == 0 {
+		// keep the old behavior, docker is the default runtime
+		log.Infof(ctx, "no container runtime socket found, use the default runtime: %s", container.DockerRuntime)
+		cli, err := newClient(container.DockerRuntime, expModel.ActionFlags[EndpointFlag.Name], "")
+		return cli, nil, err
+	}
+	errs := make([]string, 0, len(candidates))
+	for idx := range candidates {
+		candidate := candidates[idx]
+		cli, err := newClient(candidate.Runtime, candidate.Endpoint, expModel.ActionFlags[ContainerNamespace.Name])
+		if err != nil {
+			log.Debugf(ctx, "runtime %s is not available by %s, %v", candidate.Runtime, candidate.Endpoint, err)
+			errs = append(errs, fmt.Sprintf("%s(%s): %v", candidate.Runtime, candidate.Endpoint, err))
+			continue
+		}
+		if err := lookupTargetContainer(ctx, cli, candidate.Runtime, expModel); err != nil {
+			log.Debugf(ctx, "runtime %s by %s does not know the target container, %v", candidate.Runtime, candidate.Endpoint, err)
+			errs = append(errs, fmt.Sprintf("%s(%s): %v", candidate.Runtime, candidate.Endpoint, err))
+			continue
+		}
+		log.Infof(ctx, "detected container runtime: %s, endpoint: %s", candidate.Runtime, candidate.Endpoint)
+		return cli, &candidate, nil
+	}
+	return nil, nil, fmt.Errorf("cannot detect the container runtime of the target container, please specify the --%s flag, %s",
+		ContainerRuntime.Name, strings.Join(errs, "; "))
+}
+
+// lookupTargetContainer checks the client knows the container of the experiment. The containers in the moby
+// namespace of containerd are managed by docker, so they are not targeted by containerd
+func lookupTargetContainer(ctx context.Context, cli container.Container, runtime string, expModel *spec.ExpModel) error {
+	filter, response := GetContainerFilter(ctx, expModel)
+	if !response.Success {
+		return errors.New(response.Err)
+	}
+	if filter.IsEmpty() {
+		// the first available runtime is used if there is no container flag, GetContainer will report it later
+		return nil
+	}
+	containers, err, _ := cli.GetContainers(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if runtime == container.ContainerdRuntime {
+		managed := containers[:0]
+		for _, ctr := range containers {
+			if ctr.Namespace != containerd.MobyNS {
+				managed = append(managed, ctr)
+			}
+		}
+		containers = managed
+	}
+	if len(containers) == 0 {
+		return errors.New("container not found")
+	}
+	return nil
+}
+
+// runtimeCandidates returns the runtimes and endpoints which may serve the target container
+func runtimeCandidates(ctx context.Context, endpoint string) []RuntimeDetection {
+	if endpoint != "" {
+		return []RuntimeDetection{{Runtime: runtimeOfEndpoint(ctx, endpoint), Endpoint: endpoint}}
+	}
+	candidates := make([]RuntimeDetection, 0)
+	seen := make(map[string]bool)
+	add := func(candidate RuntimeDetection) {
+		socket := strings.TrimPrefix(candidate.Endpoint, "unix://")
+		if seen[socket] {
+			return
+		}
+		if _, err := os.Stat(socket); err != nil {
+			return
+		}
+		seen[socket] = true
+		candidates = append(candidates, candidate)
+	}
+	if envEndpoint := os.Getenv(cri.RuntimeEndpointEnv); envEndpoint != "" {
+		add(RuntimeDetection{Runtime: runtimeOfEndpoint(ctx, envEndpoint), Endpoint: envEndpoint})
+	}
+	if crictlEndpoint := readCrictlEndpoint(CrictlConfigFile); crictlEndpoint != "" {
+		add(RuntimeDetection{Runtime: runtimeOfEndpoint(ctx, crictlEndpoint), Endpoint: crictlEndpoint})
+	}
+	for _, candidate := range runtimeSockets {
+		add(candidate)
+	}
+	return candidates
+}
+
+// runtimeOfEndpoint returns the runtime by the well-known socket, docker is used for the remote endpoints.
+// The runtime of an unknown socket is probed, docker is still used if the socket does not answer
+func runtimeOfEndpoint(ctx context.Context, endpoint string) string {
+	if container.IsRemoteEndpoint(endpoint) {
+		return container.DockerRuntime
+	}
+	socket := strings.TrimPrefix(endpoint, "unix://")
+	for _, candidate := range runtimeSockets {
+		if strings.TrimPrefix(candidate.Endpoint, "unix://") == socket {
+			return candidate.Runtime
+		}
+	}
+	if strings.HasSuffix(socket, "podman.sock") {
+		return container.PodmanRuntime
+	}
+	runtime, err := probeRuntime(ctx, socket)
+	if err != nil {
+		log.Warnf(ctx, "cannot probe the container runtime of %s, use the default runtime: %s, %v", endpoint,
+			container.DockerRuntime, err)
+		return container.DockerRuntime
+	}
+	log.Debugf(ctx, "the container runtime of %s is %s", endpoint, runtime)
+	return runtime
+}
+
+// probeTimeout is the timeout of each probe of an unknown socket
+const probeTimeout = 2 * time.Second
+
+// probeRuntime returns the runtime which serves the socket. The docker api is probed first, podman answers it too
+// but adds the libpod version header. The sockets which speak grpc are the cri runtime service or containerd
+func probeRuntime(ctx context.Context, socket string) (string, error) {
+	var errs []string
+	runtime, err := probeDockerAPI(ctx, socket)
+	if err == nil {
+		return runtime, nil
+	}
+	errs = append(errs, fmt.Sprintf("docker: %v", err))
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	runtime, err = probeGRPC(ctx, socket)
+	if err == nil {
+		return runtime, nil
+	}
+	errs = append(errs, err.Error())
+	return "", errors.New(strings.Join(errs, "; "))
+}
+
+// probeDockerAPI requests the /_ping of the docker api by the socket
+func probeDockerAPI(ctx context.Context, socket string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	defer httpClient.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://d/_ping", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Api-Version") == "" {
+		return "", fmt.Errorf("unexpected response of /_ping: %s", resp.Status)
+	}
+	if resp.Header.Get("Libpod-Api-Version") != "" {
+		return container.PodmanRuntime, nil
+	}
+	return container.DockerRuntime, nil
+}
+
+// probeGRPC calls the version of the cri runtime service and then the version of containerd by the socket
+func probeGRPC(ctx context.Context, socket string) (string, error) {
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	criCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	_, criErr := runtimeapi.NewRuntimeServiceClient(conn).Version(criCtx, &runtimeapi.VersionRequest{})
+	cancel()
+	if criErr == nil {
+		return container.CriRuntime, nil
+	}
+	containerdCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	_, containerdErr := versionservice.NewVersionClient(conn).Version(containerdCtx, &emptypb.Empty{})
+	cancel()
+	if containerdErr == nil {
+		return container.ContainerdRuntime, nil
+	}
+	return "", fmt.Errorf("cri: %v; containerd: %v", criErr, containerdErr)
+}
+
+// readCrictlEndpoint returns the runtime-endpoint in the crictl config file
+func readCrictlEndpoint(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		RuntimeEndpoint string `yaml:"runtime-endpoint"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+	return config.RuntimeEndpoint
+}
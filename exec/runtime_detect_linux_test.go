@@ -0,0 +1,169 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	versionservice "github.com/containerd/containerd/api/services/version/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container/cri"
+)
+
+type fakeRuntimeService struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+}
+
+func (*fakeRuntimeService) Version(context.Context, *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{RuntimeName: "fake"}, nil
+}
+
+type fakeVersionService struct {
+	versionservice.UnimplementedVersionServer
+}
+
+func (*fakeVersionService) Version(context.Context, *emptypb.Empty) (*versionservice.VersionResponse, error) {
+	return &versionservice.VersionResponse{Version: "v1.7.23"}, nil
+}
+
+func listenUnix(t *testing.T) (net.Listener, string) {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "runtime.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen %s: %v", socket, err)
+	}
+	return listener, socket
+}
+
+// startPingServer serves the /_ping of the docker api with the headers
+func startPingServer(t *testing.T, headers map[string]string) string {
+	listener, socket := listenUnix(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ping" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+		w.Write([]byte("OK"))
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return socket
+}
+
+// startGRPCServer serves the services registered by the register function
+func startGRPCServer(t *testing.T, register func(server *grpc.Server)) string {
+	listener, socket := listenUnix(t)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return socket
+}
+
+func TestRuntimeOfEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint func(t *testing.T) string
+		want     string
+	}{
+		{"remote endpoint", func(*testing.T) string { return "tcp://127.0.0.1:2375" }, container.DockerRuntime},
+		{"well-known containerd socket", func(*testing.T) string { return "unix:///run/containerd/containerd.sock" },
+			container.ContainerdRuntime},
+		{"well-known crio socket", func(*testing.T) string { return "/var/run/crio/crio.sock" }, container.CrioRuntime},
+		{"podman socket", func(*testing.T) string { return "unix:///run/user/1000/podman/podman.sock" },
+			container.PodmanRuntime},
+		{"custom docker socket", func(t *testing.T) string {
+			return "unix://" + startPingServer(t, map[string]string{"Api-Version": "1.47"})
+		}, container.DockerRuntime},
+		{"custom podman socket", func(t *testing.T) string {
+			return startPingServer(t, map[string]string{"Api-Version": "1.41", "Libpod-Api-Version": "4.9.3"})
+		}, container.PodmanRuntime},
+		{"custom cri socket", func(t *testing.T) string {
+			return "unix://" + startGRPCServer(t, func(server *grpc.Server) {
+				runtimeapi.RegisterRuntimeServiceServer(server, &fakeRuntimeService{})
+			})
+		}, container.CriRuntime},
+		{"custom containerd socket", func(t *testing.T) string {
+			return "unix://" + startGRPCServer(t, func(server *grpc.Server) {
+				versionservice.RegisterVersionServer(server, &fakeVersionService{})
+			})
+		}, container.ContainerdRuntime},
+		{"missing socket falls back to docker", func(t *testing.T) string {
+			return "unix://" + filepath.Join(t.TempDir(), "missing.sock")
+		}, container.DockerRuntime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runtimeOfEndpoint(context.Background(), tt.endpoint(t)); got != tt.want {
+				t.Errorf("runtimeOfEndpoint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuntimeOfEndpointCanceled(t *testing.T) {
+	// the socket accepts the connections but never answers
+	listener, socket := listenUnix(t)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if got := runtimeOfEndpoint(ctx, "unix://"+socket); got != container.DockerRuntime {
+		t.Errorf("runtimeOfEndpoint() = %s, want %s", got, container.DockerRuntime)
+	}
+	if elapsed := time.Since(start); elapsed >= probeTimeout {
+		t.Errorf("runtimeOfEndpoint() took %s, the canceled context is ignored", elapsed)
+	}
+}
+
+func TestRuntimeCandidatesProbesEnvEndpoint(t *testing.T) {
+	socket := startPingServer(t, map[string]string{"Api-Version": "1.47"})
+	t.Setenv(cri.RuntimeEndpointEnv, "unix://"+socket)
+	candidates := runtimeCandidates(context.Background(), "")
+	if len(candidates) == 0 {
+		t.Fatalf("runtimeCandidates() is empty")
+	}
+	if candidates[0].Endpoint != "unix://"+socket || candidates[0].Runtime != container.DockerRuntime {
+		t.Errorf("runtimeCandidates()[0] = %+v, want docker by %s", candidates[0], socket)
+	}
+}
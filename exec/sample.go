@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// isSampling returns true if the container-count or container-percent flag is specified
+func isSampling(expModel *spec.ExpModel) bool {
+	return expModel.ActionFlags[ContainerCountFlag.Name] != "" || expModel.ActionFlags[ContainerPercentFlag.Name] != ""
+}
+
+// sampleContainers picks the random subset of the containers by the container-count or container-percent flag,
+// the same seed always picks the same containers from the same matched containers
+func sampleContainers(ctx context.Context, expModel *spec.ExpModel, containers []container.ContainerInfo) ([]container.ContainerInfo, *spec.Response) {
+	countValue := expModel.ActionFlags[ContainerCountFlag.Name]
+	percentValue := expModel.ActionFlags[ContainerPercentFlag.Name]
+	if countValue != "" && percentValue != "" {
+		log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerCountFlag.Name, countValue, "cannot be used with container-percent"))
+		return nil, spec.ResponseFailWithFlags(spec.ParameterInvalid, ContainerCountFlag.Name, countValue, "cannot be used with container-percent")
+	}
+	var count int
+	if countValue != "" {
+		value, err := strconv.Atoi(countValue)
+		if err != nil || value <= 0 {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerCountFlag.Name, countValue, "it must be a positive integer"))
+			return nil, spec.ResponseFailWithFlags(spec.ParameterInvalid, ContainerCountFlag.Name, countValue, "it must be a positive integer")
+		}
+		count = value
+	} else {
+		percent, err := strconv.ParseFloat(percentValue, 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(ContainerPercentFlag.Name, percentValue, "it must be in (0, 100]"))
+			return nil, spec.ResponseFailWithFlags(spec.ParameterInvalid, ContainerPercentFlag.Name, percentValue, "it must be in (0, 100]")
+		}
+		count = int(math.Ceil(float64(len(containers)) * percent / 100))
+	}
+	if count >= len(containers) {
+		return containers, nil
+	}
+
+	seed := time.Now().UnixNano()
+	if seedValue := expModel.ActionFlags[SeedFlag.Name]; seedValue != "" {
+		value, err := strconv.ParseInt(seedValue, 10, 64)
+		if err != nil {
+			log.Errorf(ctx, "%s", spec.ParameterInvalid.Sprintf(SeedFlag.Name, seedValue, "it must be an integer"))
+			return nil, spec.ResponseFailWithFlags(spec.ParameterInvalid, SeedFlag.Name, seedValue, "it must be an integer")
+		}
+		seed = value
+	}
+	// sort the containers first, the order of the containers returned by the runtime is not stable
+	sampled := make([]container.ContainerInfo, len(containers))
+	copy(sampled, containers)
+	sort.Slice(sampled, func(i, j int) bool {
+		return sampled[i].ContainerId < sampled[j].ContainerId
+	})
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(len(sampled), func(i, j int) {
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	})
+	sampled = sampled[:count]
+	log.Infof(ctx, "%d of %d matched containers are sampled by the seed %d", count, len(containers), seed)
+	return sampled, nil
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+func newSampleModel(count, percent, seed string) *spec.ExpModel {
+	return &spec.ExpModel{ActionFlags: map[string]string{
+		ContainerCountFlag.Name:   count,
+		ContainerPercentFlag.Name: percent,
+		SeedFlag.Name:             seed,
+	}}
+}
+
+func newSampleContainers(n int) []container.ContainerInfo {
+	containers := make([]container.ContainerInfo, 0, n)
+	for idx := 0; idx < n; idx++ {
+		containers = append(containers, container.ContainerInfo{ContainerId: fmt.Sprintf("container-%02d", idx)})
+	}
+	return containers
+}
+
+func containerIds(containers []container.ContainerInfo) []string {
+	ids := make([]string, 0, len(containers))
+	for _, ctr := range containers {
+		ids = append(ids, ctr.ContainerId)
+	}
+	return ids
+}
+
+func TestSampleContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    string
+		percent  string
+		seed     string
+		matched  int
+		want     int
+		wantCode int32
+	}{
+		{name: "count", count: "3", seed: "1", matched: 10, want: 3},
+		{name: "count exceeds the matched", count: "20", matched: 10, want: 10},
+		{name: "percent rounds up", percent: "25", seed: "1", matched: 10, want: 3},
+		{name: "fractional percent", percent: "0.5", seed: "1", matched: 10, want: 1},
+		{name: "hundred percent", percent: "100", matched: 10, want: 10},
+		{name: "count and percent", count: "1", percent: "50", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "zero count", count: "0", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "negative count", count: "-1", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "non-integer count", count: "1.5", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "zero percent", percent: "0", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "percent over hundred", percent: "101", matched: 10, wantCode: spec.ParameterInvalid.Code},
+		{name: "invalid seed", count: "1", seed: "abc", matched: 10, wantCode: spec.ParameterInvalid.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampled, resp := sampleContainers(context.Background(), newSampleModel(tt.count, tt.percent, tt.seed),
+				newSampleContainers(tt.matched))
+			if tt.wantCode != 0 {
+				if resp == nil || resp.Code != tt.wantCode {
+					t.Fatalf("sampleContainers() = %v, want code %d", resp, tt.wantCode)
+				}
+				return
+			}
+			if resp != nil {
+				t.Fatalf("sampleContainers() err = %s", resp.Err)
+			}
+			if len(sampled) != tt.want {
+				t.Errorf("sampleContainers() sampled %d containers, want %d", len(sampled), tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleContainersBySeed(t *testing.T) {
+	containers := newSampleContainers(10)
+	sampled, _ := sampleContainers(context.Background(), newSampleModel("4", "", "42"), containers)
+
+	// the runtime returns the containers in any order
+	reversed := make([]container.ContainerInfo, 0, len(containers))
+	for idx := len(containers) - 1; idx >= 0; idx-- {
+		reversed = append(reversed, containers[idx])
+	}
+	again, _ := sampleContainers(context.Background(), newSampleModel("4", "", "42"), reversed)
+	if !reflect.DeepEqual(containerIds(sampled), containerIds(again)) {
+		t.Errorf("the same seed sampled %v and %v", containerIds(sampled), containerIds(again))
+	}
+	if reversed[0].ContainerId != "container-09" {
+		t.Errorf("sampleContainers() modified the matched containers")
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range containerIds(sampled) {
+		if seen[id] {
+			t.Errorf("container %s is sampled twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestSampledContainersSurviveSelectorChange(t *testing.T) {
+	useTempStateDir(t)
+	t.Setenv(PolicyFileEnv, filepath.Join(t.TempDir(), "policy.yaml"))
+	containers := newSampleContainers(10)
+	for idx := range containers {
+		containers[idx].State = container.StateRunning
+		containers[idx].Labels = map[string]string{"app": "nginx"}
+	}
+	client := &ExperimentClient{Container: &fakeContainer{containers: containers}}
+	executor := &BaseClientExecutor{Kind: ExecutorKindNSExec}
+	uid := "uid-sampled"
+	expModel := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: map[string]string{
+		ContainerLabelSelectorFlag.Name: "app=nginx",
+		ContainerCountFlag.Name:         "3",
+		SeedFlag.Name:                   "7",
+	}}
+	sampled, response := executor.GetExperimentContainers(context.Background(), client, uid, expModel)
+	if !response.Success {
+		t.Fatalf("GetExperimentContainers() = %s", response.Err)
+	}
+	inject := func(ctx context.Context, info container.ContainerInfo) *spec.Response {
+		return spec.ReturnSuccess(info.ContainerId)
+	}
+	if response := executor.FanOut(context.Background(), client, uid, expModel, sampled, inject); !response.Success {
+		t.Fatalf("FanOut() = %s", response.Err)
+	}
+
+	// the selector and the sampling flags are changed when destroying, the sampled containers are still reverted
+	destroyModel := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: map[string]string{
+		ContainerLabelSelectorFlag.Name: "app=redis",
+		ContainerCountFlag.Name:         "1",
+	}}
+	destroyed, response := executor.GetExperimentContainers(spec.SetDestroyFlag(context.Background(), uid), client, uid,
+		destroyModel)
+	if !response.Success {
+		t.Fatalf("GetExperimentContainers() = %s", response.Err)
+	}
+	if !reflect.DeepEqual(containerIds(destroyed), containerIds(sampled)) {
+		t.Errorf("destroyed %v, want the sampled %v", containerIds(destroyed), containerIds(sampled))
+	}
+}
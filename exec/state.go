@@ -0,0 +1,306 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// StateDir is the directory of the experiment states which is relative to the program path
+const StateDir = "cri-experiments"
+
+// The kinds of the executors which inject the experiments
+const (
+	ExecutorKindNSExec  = "nsexec"
+	ExecutorKindCopy    = "copy"
+	ExecutorKindSidecar = "sidecar"
+)
+
+// ExperimentState is the persistent state of the experiment, destroy and status consult it before the flags,
+// so the experiment can be recovered even if the containers are renamed or relabelled
+type ExperimentState struct {
+	Uid       string `json:"uid"`
+	Target    string `json:"target"`
+	Action    string `json:"action"`
+	Executor  string `json:"executor,omitempty"`
+	Runtime   string `json:"runtime,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Flags are the action flags of the experiment
+	Flags      map[string]string `json:"flags,omitempty"`
+	Containers []TargetContainer `json:"containers"`
+	// Revert is the automatic revert of the experiment, it is nil if the experiment is only reverted manually
+	Revert     *ScheduledRevert `json:"revert,omitempty"`
+	CreateTime time.Time        `json:"createTime"`
+	UpdateTime time.Time        `json:"updateTime"`
+}
+
+// TargetContainer is the container which the experiment is injected into
+type TargetContainer struct {
+	ContainerId   string `json:"containerId"`
+	ContainerName string `json:"containerName,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	// Pid is the pid of the container process when the experiment is injected
+	Pid int32 `json:"pid,omitempty"`
+	// HangPid is the pid of the process which keeps running for the experiment, such as cpu load
+	HangPid int `json:"hangPid,omitempty"`
+	// Executor is the kind of the executor in the container, it is copy if nsexec falls back to the exec api
+	Executor string `json:"executor,omitempty"`
+	// Argv is the full command executed for the experiment
+	Argv []string `json:"argv,omitempty"`
+}
+
+// key identifies the container by its namespace and id, the same id may exist in several containerd namespaces
+func (t TargetContainer) key() string {
+	return t.Namespace + "/" + t.ContainerId
+}
+
+// stateMu serializes the access of the goroutines, the state directory is also locked across the processes,
+// such as the concurrent blade commands and the revert watchers
+var stateMu sync.Mutex
+
+// stateRoot returns the directory where the state directory is, it is replaced by the tests
+var stateRoot = util.GetProgramPath
+
+func stateDir() string {
+	return filepath.Join(stateRoot(), StateDir)
+}
+
+func stateFile(uid string) string {
+	return filepath.Join(stateDir(), uid+".json")
+}
+
+// withStateLock calls the function with the state directory locked
+func withStateLock(fn func() error) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	unlock, err := lockStateDir(stateDir())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// SaveExperimentState writes the state of the experiment, the state is removed if it has no container
+func SaveExperimentState(state *ExperimentState) error {
+	return withStateLock(func() error {
+		return saveExperimentState(state)
+	})
+}
+
+func saveExperimentState(state *ExperimentState) error {
+	file := stateFile(state.Uid)
+	if len(state.Containers) == 0 {
+		return removeExperimentState(state.Uid)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	now := time.Now()
+	if state.CreateTime.IsZero() {
+		state.CreateTime = now
+	}
+	state.UpdateTime = now
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file and rename it, the state is never half written
+	tmpFile := file + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, file)
+}
+
+// UpdateExperimentState reads, modifies and writes the state of the experiment with the state directory locked.
+// The bool passed to the function is false if there is no state, the state is not written if the function fails
+func UpdateExperimentState(uid string, fn func(state *ExperimentState, ok bool) (*ExperimentState, error)) (*ExperimentState, error) {
+	var updated *ExperimentState
+	err := withStateLock(func() error {
+		state, ok, err := loadExperimentState(stateFile(uid))
+		if err != nil {
+			return err
+		}
+		if updated, err = fn(state, ok); err != nil {
+			return err
+		}
+		return saveExperimentState(updated)
+	})
+	return updated, err
+}
+
+// LoadExperimentState returns the state of the experiment, the bool is false if there is no state
+func LoadExperimentState(uid string) (*ExperimentState, bool, error) {
+	var state *ExperimentState
+	var ok bool
+	err := withStateLock(func() error {
+		var err error
+		state, ok, err = loadExperimentState(stateFile(uid))
+		return err
+	})
+	return state, ok, err
+}
+
+func loadExperimentState(file string) (*ExperimentState, bool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	state := &ExperimentState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, false, err
+	}
+	return state, true, nil
+}
+
+// ListExperimentStates returns the states of all the experiments ordered by the create time
+func ListExperimentStates() ([]*ExperimentState, error) {
+	var states []*ExperimentState
+	err := withStateLock(func() error {
+		var err error
+		states, err = listExperimentStates()
+		return err
+	})
+	return states, err
+}
+
+func listExperimentStates() ([]*ExperimentState, error) {
+	files, err := filepath.Glob(filepath.Join(stateDir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*ExperimentState, 0, len(files))
+	for _, file := range files {
+		state, ok, err := loadExperimentState(file)
+		if err != nil || !ok {
+			continue
+		}
+		if state.Target == "" {
+			// the targets reserved by the policy of the previous versions, they are removed when they expire
+			if time.Since(state.UpdateTime) > ReservationTTL {
+				os.Remove(file)
+			}
+			continue
+		}
+		if state.Uid == "" {
+			state.Uid = strings.TrimSuffix(filepath.Base(file), ".json")
+		}
+		states = append(states, state)
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].CreateTime.Before(states[j].CreateTime)
+	})
+	return states, nil
+}
+
+// RemoveExperimentState removes the state of the experiment
+func RemoveExperimentState(uid string) error {
+	return withStateLock(func() error {
+		return removeExperimentState(uid)
+	})
+}
+
+func removeExperimentState(uid string) error {
+	if err := os.Remove(stateFile(uid)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// countActiveTargets returns the number of the containers injected or reserved by the experiments except the uid,
+// it is called with the state directory locked
+func countActiveTargets(uid string) int {
+	states, err := listExperimentStates()
+	if err != nil {
+		return 0
+	}
+	count := 0
+	injected := make(map[string]bool, len(states))
+	for _, state := range states {
+		injected[state.Uid] = true
+		if state.Uid != uid {
+			count += len(state.Containers)
+		}
+	}
+	for _, reserved := range listReservations() {
+		// the reservation may not be released yet after the state is saved
+		if reserved.Uid != uid && !injected[reserved.Uid] {
+			count += reserved.Count
+		}
+	}
+	return count
+}
+
+type containerRecordKey struct{}
+
+// withContainerRecord returns the context which collects the execution of the experiment in the container
+func withContainerRecord(ctx context.Context, record *TargetContainer) context.Context {
+	return context.WithValue(ctx, containerRecordKey{}, record)
+}
+
+// recordContainerExec records the container pid and the command of the experiment
+func recordContainerExec(ctx context.Context, pid int32, argv []string) {
+	if record, ok := ctx.Value(containerRecordKey{}).(*TargetContainer); ok {
+		record.Pid = pid
+		record.Argv = argv
+	}
+}
+
+// recordContainerExecutor records the kind of the executor which executes the experiment in the container
+func recordContainerExecutor(ctx context.Context, kind string) {
+	if record, ok := ctx.Value(containerRecordKey{}).(*TargetContainer); ok {
+		record.Executor = kind
+	}
+}
+
+// recordedContainerExecutor returns the kind of the executor recorded for the container at creation,
+// it is empty if the container is not recorded
+func recordedContainerExecutor(uid, containerId, namespace string) string {
+	state, ok, err := LoadExperimentState(uid)
+	if err != nil || !ok {
+		return ""
+	}
+	key := TargetContainer{ContainerId: containerId, Namespace: namespace}.key()
+	for _, record := range state.Containers {
+		if record.key() == key {
+			return record.Executor
+		}
+	}
+	return ""
+}
+
+// recordHangProcess records the process which keeps running for the experiment
+func recordHangProcess(ctx context.Context, pid int) {
+	if record, ok := ctx.Value(containerRecordKey{}).(*TargetContainer); ok {
+		record.HangPid = pid
+	}
+}
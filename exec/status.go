@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"context"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-exec-cri/exec/container"
+)
+
+// ExperimentStatus is the status of the active cri experiment on the node
+type ExperimentStatus struct {
+	Uid        string            `json:"uid"`
+	Target     string            `json:"target"`
+	Action     string            `json:"action"`
+	Executor   string            `json:"executor,omitempty"`
+	Runtime    string            `json:"runtime,omitempty"`
+	Endpoint   string            `json:"endpoint,omitempty"`
+	Flags      map[string]string `json:"flags,omitempty"`
+	StartTime  time.Time         `json:"startTime"`
+	Revert     *ScheduledRevert  `json:"revert,omitempty"`
+	Containers []ContainerStatus `json:"containers"`
+	// Err is the reason if the containers can not be queried from the runtime
+	Err string `json:"error,omitempty"`
+}
+
+// ContainerStatus is the status of the container which the experiment is injected into
+type ContainerStatus struct {
+	ContainerId   string `json:"containerId"`
+	ContainerName string `json:"containerName,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	// Exists is false if the container is removed or recreated
+	Exists bool   `json:"exists"`
+	State  string `json:"state,omitempty"`
+	// Executor is the kind of the executor which the experiment is executed by in the container
+	Executor string `json:"executor,omitempty"`
+	// HangPid is the pid of the process which keeps running for the experiment
+	HangPid   int                    `json:"hangPid,omitempty"`
+	HangAlive bool                   `json:"hangAlive"`
+	Pod       *container.PodIdentity `json:"pod,omitempty"`
+}
+
+// ListExperiments returns the status of all the active cri experiments on the node
+func ListExperiments(ctx context.Context) ([]ExperimentStatus, error) {
+	states, err := ListExperimentStates()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]ExperimentStatus, 0, len(states))
+	for _, state := range states {
+		statuses = append(statuses, experimentStatus(ctx, state))
+	}
+	return statuses, nil
+}
+
+// GetExperimentStatus returns the status of the cri experiment, the bool is false if the experiment is not active
+func GetExperimentStatus(ctx context.Context, uid string) (ExperimentStatus, bool, error) {
+	state, ok, err := LoadExperimentState(uid)
+	if err != nil || !ok {
+		return ExperimentStatus{}, false, err
+	}
+	return experimentStatus(ctx, state), true, nil
+}
+
+// QueryExperiments returns the response which contains the status of the experiment, or of all the active
+// experiments if the uid is empty. The revert watchers which are not running are resumed before querying
+func QueryExperiments(ctx context.Context, uid string) *spec.Response {
+	resumeRevertWatchers(ctx)
+	if uid == "" {
+		statuses, err := ListExperiments(ctx)
+		if err != nil {
+			log.Errorf(ctx, "%s", spec.DbQueryFailed.Sprintf("experiment states", err))
+			return spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiment states", err)
+		}
+		return spec.ReturnSuccess(statuses)
+	}
+	status, ok, err := GetExperimentStatus(ctx, uid)
+	if err != nil {
+		log.Errorf(ctx, "%s", spec.DbQueryFailed.Sprintf("experiment state", err))
+		return spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiment state", err)
+	}
+	if !ok {
+		log.Errorf(ctx, "%s", ExperimentNotFound.Sprintf(uid))
+		return spec.ResponseFailWithFlags(ExperimentNotFound, uid)
+	}
+	return spec.ReturnSuccess(status)
+}
+
+func experimentStatus(ctx context.Context, state *ExperimentState) ExperimentStatus {
+	status := ExperimentStatus{
+		Uid:        state.Uid,
+		Target:     state.Target,
+		Action:     state.Action,
+		Executor:   state.Executor,
+		Runtime:    state.Runtime,
+		Endpoint:   state.Endpoint,
+		Flags:      state.Flags,
+		StartTime:  state.CreateTime,
+		Revert:     state.Revert,
+		Containers: make([]ContainerStatus, 0, len(state.Containers)),
+	}
+	client, err := stateClient(ctx, state)
+	if err != nil {
+		status.Err = err.Error()
+	}
+	for _, target := range state.Containers {
+		containerStatus := ContainerStatus{
+			ContainerId:   target.ContainerId,
+			ContainerName: target.ContainerName,
+			Namespace:     target.Namespace,
+			Executor:      target.Executor,
+			HangPid:       target.HangPid,
+			HangAlive:     isExperimentProcessAlive(target.HangPid, state.Uid),
+		}
+		if err == nil {
+			containers, queryErr, _ := client.GetContainers(ctx, container.ContainerFilter{
+				ContainerId:    target.ContainerId,
+				Namespace:      target.Namespace,
+				IncludeSandbox: true,
+			})
+			if queryErr != nil {
+				status.Err = queryErr.Error()
+			} else if len(containers) > 0 {
+				containerStatus.Exists = true
+				containerStatus.State = containers[0].State
+				containerStatus.Pod = container.GetPodIdentity(containers[0].Labels)
+			}
+		}
+		status.Containers = append(status.Containers, containerStatus)
+	}
+	return status
+}
+
+// stateClient returns the client of the runtime recorded in the experiment state
+func stateClient(ctx context.Context, state *ExperimentState) (container.Container, error) {
+	client, _, err := getClient(ctx, stateExpModel(state, state.Flags))
+	return client, err
+}
+
+// stateExpModel returns the experiment model whose runtime, endpoint and namespace are recorded in the state
+func stateExpModel(state *ExperimentState, actionFlags map[string]string) *spec.ExpModel {
+	flags := make(map[string]string, len(actionFlags)+3)
+	for k, v := range actionFlags {
+		flags[k] = v
+	}
+	flags[ContainerRuntime.Name] = state.Runtime
+	flags[EndpointFlag.Name] = state.Endpoint
+	flags[ContainerNamespace.Name] = state.Namespace
+	return &spec.ExpModel{
+		Target:      state.Target,
+		ActionName:  state.Action,
+		ActionFlags: flags,
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exec
+
+import (
+	"fmt"
+	"os"
+)
+
+// needJoinUserNamespace returns true if the target process is in another user namespace which the current process
+// has no privilege over, for example the container of the rootless podman when blade is not run by root
+func needJoinUserNamespace(pid int32) bool {
+	if os.Geteuid() == 0 {
+		return false
+	}
+	self, err := os.Readlink("/proc/self/ns/user")
+	if err != nil {
+		return false
+	}
+	target, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/user", pid))
+	if err != nil {
+		return false
+	}
+	return self != target
+}